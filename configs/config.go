@@ -3,16 +3,20 @@ package configs
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server      ServerConfig
-	Database    DatabaseConfig
-	Logging     LoggingConfig
-	Auth        AuthConfig
-	EmailSender EmailSender
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Logging       LoggingConfig
+	Auth          AuthConfig
+	EmailSender   EmailSender
+	Conferencing  ConferencingConfig
+	Public        PublicConfig
+	Notifications NotificationsConfig
 }
 
 type ServerConfig struct {
@@ -40,12 +44,46 @@ type EmailSender struct {
 	SmtpWithPort string
 	Smtp         string
 }
+type ConferencingConfig struct {
+	BaseURL string
+}
+
+// PublicConfig адрес, по которому сервис доступен из писем и внешних ссылок
+type PublicConfig struct {
+	BaseURL string
+}
+
+// NotificationsConfig каналы доставки уведомлений
+type NotificationsConfig struct {
+	// Channels включенные каналы: smtp, webhook, inapp, log
+	Channels      []string
+	WebhookURL    string
+	WebhookSecret string
+	// LogFile файл для канала log, по умолчанию уведомления пишутся в журнал приложения
+	LogFile string
+	// TemplatesDir каталог с шаблонами писем, заменяющими встроенные
+	TemplatesDir string
+}
+
+// defaultNotificationChannels используются, если NOTIFY_CHANNELS не задан
+const defaultNotificationChannels = "smtp,inapp"
+
+// defaultPublicBaseURL используется, если PUBLIC_BASE_URL не задан
+const defaultPublicBaseURL = "http://localhost:8080"
 
 func LoadConfig() *Config {
 	err := godotenv.Load(".env")
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
+	publicBaseURL := strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/")
+	if publicBaseURL == "" {
+		publicBaseURL = defaultPublicBaseURL
+	}
+	notifyChannels := os.Getenv("NOTIFY_CHANNELS")
+	if notifyChannels == "" {
+		notifyChannels = defaultNotificationChannels
+	}
 	return &Config{
 		Server: ServerConfig{
 			Port: os.Getenv("SERVER_PORT"),
@@ -70,5 +108,18 @@ func LoadConfig() *Config {
 			SmtpWithPort: os.Getenv("SMTPWITHPORT"),
 			Smtp:         os.Getenv("SMTP"),
 		},
+		Conferencing: ConferencingConfig{
+			BaseURL: os.Getenv("CONFERENCING_URL"),
+		},
+		Public: PublicConfig{
+			BaseURL: publicBaseURL,
+		},
+		Notifications: NotificationsConfig{
+			Channels:      strings.Split(notifyChannels, ","),
+			WebhookURL:    os.Getenv("NOTIFY_WEBHOOK_URL"),
+			WebhookSecret: os.Getenv("NOTIFY_WEBHOOK_SECRET"),
+			LogFile:       os.Getenv("NOTIFY_LOG_FILE"),
+			TemplatesDir:  os.Getenv("NOTIFY_TEMPLATES_DIR"),
+		},
 	}
 }
@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeLoad(t *testing.T) {
+	// понедельник и вторник
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+	at := func(day, hour, minute int) time.Time {
+		return from.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	spans := []models.MeetingSpan{
+		{UserID: 1, EventID: 10, StartDate: at(0, 10, 0), Duration: 60},
+		// пересекается с предыдущей встречей
+		{UserID: 1, EventID: 11, StartDate: at(0, 10, 30), Duration: 60},
+		{UserID: 1, EventID: 12, StartDate: at(1, 9, 0), Duration: 120},
+	}
+	load := computeLoad(1, spans, map[uint][]uint{10: {5}, 12: {5, 6}}, from, to)
+
+	require.Equal(t, 3, load.Meetings)
+	require.InDelta(t, 3.5, load.MeetingHours, 0.001)
+	require.InDelta(t, 3.5/16, load.WorkingWeekShare, 0.001)
+	require.InDelta(t, 3.0, load.TagHours[5], 0.001)
+	require.InDelta(t, 2.0, load.TagHours[6], 0.001)
+	require.Len(t, load.FocusBlocks, 3)
+	// самый длинный промежуток: вторник 11:00-17:00
+	require.Equal(t, at(1, 11, 0), load.FocusBlocks[0].Start)
+	require.Equal(t, 360, load.FocusBlocks[0].Minutes)
+	require.Equal(t, 330, load.FocusBlocks[1].Minutes)
+	require.Equal(t, 60, load.FocusBlocks[2].Minutes)
+}
+
+func TestComputeLoadWeekend(t *testing.T) {
+	saturday := time.Date(2025, 6, 7, 0, 0, 0, 0, time.UTC)
+	spans := []models.MeetingSpan{{UserID: 1, EventID: 1, StartDate: saturday.Add(10 * time.Hour), Duration: 60}}
+	load := computeLoad(1, spans, nil, saturday, saturday.AddDate(0, 0, 2))
+
+	require.InDelta(t, 1.0, load.MeetingHours, 0.001)
+	require.Zero(t, load.WorkingWeekShare)
+	require.Empty(t, load.FocusBlocks)
+}
+
+func TestParsePeriod(t *testing.T) {
+	// среда
+	now := time.Date(2025, 6, 4, 15, 0, 0, 0, time.UTC)
+	from, to, err := parsePeriod(httptest.NewRequest("GET", "/analytics/meeting-load", nil), now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parsePeriod(httptest.NewRequest("GET", "/analytics/meeting-load?from=2025-06-01&to=2025-06-30", nil), now)
+	require.NoError(t, err)
+	require.Equal(t, 30*24*time.Hour, to.Sub(from))
+
+	_, _, err = parsePeriod(httptest.NewRequest("GET", "/analytics/meeting-load?from=2025-01-01&to=2025-12-31", nil), now)
+	require.Error(t, err)
+}
+
+func TestAcceptedMeetings(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	from := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ep.user_id, e.id AS event_id, e.start_date, e.duration FROM event_participants ep `+
+		`JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL AND e.focus = false `+
+		`WHERE (ep.deleted_at IS NULL AND ep.status = $1 AND ep.user_id IN ($2,$3)) `+
+		`AND (e.start_date < $4 AND e.start_date + (e.duration || ' minutes')::interval > $5) ORDER BY ep.user_id, e.start_date`)).
+		WithArgs(models.StatusAccepted, uint(1), uint(2), to, from).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "event_id", "start_date", "duration"}).
+			AddRow(1, 10, from.Add(10*time.Hour), 30))
+
+	repo := NewAnalyticsRepository(&db.Db{DB: gormDB})
+	spans, err := repo.AcceptedMeetings([]uint{1, 2}, from, to)
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	require.Equal(t, uint(10), spans[0].EventID)
+	require.Equal(t, 30, spans[0].Duration)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,203 @@
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/tag"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxPeriodDays предельная длина периода отчета
+	maxPeriodDays = 93
+	dateLayout    = "2006-01-02"
+)
+
+type AnalyticsHandler struct {
+	AnalyticsRepository *AnalyticsRepository
+	UserRepository      *user.UserRepository
+	GroupRepository     *group.GroupRepository
+	Tags                *tag.TagRepository
+	JWTService          *jwt.JWT
+}
+
+type AnalyticsHandlerDeps struct {
+	AnalyticsRepository *AnalyticsRepository
+	UserRepository      *user.UserRepository
+	GroupRepository     *group.GroupRepository
+	Tags                *tag.TagRepository
+	JWTService          *jwt.JWT
+}
+
+// NewAnalyticsHandler регистрирует обработчики аналитики
+func NewAnalyticsHandler(mux *chi.Mux, deps AnalyticsHandlerDeps) {
+	handler := &AnalyticsHandler{
+		AnalyticsRepository: deps.AnalyticsRepository,
+		UserRepository:      deps.UserRepository,
+		GroupRepository:     deps.GroupRepository,
+		Tags:                deps.Tags,
+		JWTService:          deps.JWTService,
+	}
+	mux.Handle("GET /analytics/meeting-load", middleware.IsAuthed(handler.MeetingLoad(), handler.JWTService))
+}
+
+// MeetingLoad возвращает нагрузку встречами за период: свою, другого пользователя организации
+// для администратора или участников группы для ее менеджера и администратора.
+// Период задается датами from и to включительно, по умолчанию текущая неделя.
+func (h *AnalyticsHandler) MeetingLoad() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		from, to, err := parsePeriod(r, time.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		currentUser, err := h.UserRepository.FindByid(userID)
+		if err != nil {
+			http.Error(w, "User not found", http.StatusUnauthorized)
+			return
+		}
+		isAdmin := currentUser.OrganizationID != models.DefaultOrganizationID && currentUser.OrgRole == models.OrgRoleAdmin
+
+		response := MeetingLoadResponse{From: from, To: to}
+		userIDs := []uint{userID}
+		query := r.URL.Query()
+		switch {
+		case query.Get("group_id") != "":
+			groupID, err := strconv.ParseUint(query.Get("group_id"), 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid group_id param", http.StatusBadRequest)
+				return
+			}
+			foundGroup, err := h.GroupRepository.FindById(uint(groupID))
+			if err != nil || foundGroup.OrganizationID != currentUser.OrganizationID {
+				http.Error(w, "Group not found", http.StatusNotFound)
+				return
+			}
+			isManager, err := h.GroupRepository.IsManager(foundGroup.ID, userID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !isManager && !isAdmin {
+				http.Error(w, "Only group managers can view team load", http.StatusForbidden)
+				return
+			}
+			userIDs, err = h.GroupRepository.MemberIDs(foundGroup.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			response.GroupID = &foundGroup.ID
+		case query.Get("user_id") != "":
+			targetID, err := strconv.ParseUint(query.Get("user_id"), 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid user_id param", http.StatusBadRequest)
+				return
+			}
+			if uint(targetID) != userID {
+				if !isAdmin {
+					http.Error(w, "Only organization admins can view other users", http.StatusForbidden)
+					return
+				}
+				if _, err := h.UserRepository.FindInOrganization(uint(targetID), currentUser.OrganizationID); err != nil {
+					http.Error(w, "User not found", http.StatusNotFound)
+					return
+				}
+			}
+			userIDs = []uint{uint(targetID)}
+		}
+
+		spans, err := h.AnalyticsRepository.AcceptedMeetings(userIDs, from, to)
+		if err != nil {
+			http.Error(w, "Failed to fetch meetings", http.StatusInternalServerError)
+			return
+		}
+		eventTags, err := h.eventTags(spans)
+		if err != nil {
+			http.Error(w, "Failed to fetch event tags", http.StatusInternalServerError)
+			return
+		}
+		byUser := make(map[uint][]models.MeetingSpan, len(userIDs))
+		for _, span := range spans {
+			byUser[span.UserID] = append(byUser[span.UserID], span)
+		}
+		response.Users = make([]UserLoad, 0, len(userIDs))
+		for _, id := range userIDs {
+			load := computeLoad(id, byUser[id], eventTags, from, to)
+			response.TotalMeetingHours += load.MeetingHours
+			response.AverageShare += load.WorkingWeekShare
+			response.Users = append(response.Users, load)
+		}
+		if len(response.Users) > 0 {
+			response.AverageShare /= float64(len(response.Users))
+		}
+		res.JsonResponse(w, response, http.StatusOK)
+	}
+}
+
+// eventTags возвращает метки встреч одним запросом
+func (h *AnalyticsHandler) eventTags(spans []models.MeetingSpan) (map[uint][]uint, error) {
+	seen := make(map[uint]bool, len(spans))
+	eventIDs := make([]uint, 0, len(spans))
+	for _, span := range spans {
+		if !seen[span.EventID] {
+			seen[span.EventID] = true
+			eventIDs = append(eventIDs, span.EventID)
+		}
+	}
+	links, err := h.Tags.FindEventTags(eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[uint][]uint, len(links))
+	for _, link := range links {
+		tags[link.EventID] = append(tags[link.EventID], link.TagID)
+	}
+	return tags, nil
+}
+
+// parsePeriod разбирает период отчета [from, to+1 день). Без параметров берется текущая неделя с понедельника.
+func parsePeriod(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	weekday := (int(today.Weekday()) + 6) % 7
+	from := today.AddDate(0, 0, -weekday)
+	to := from.AddDate(0, 0, 7)
+
+	query := r.URL.Query()
+	if fromStr := query.Get("from"); fromStr != "" {
+		parsed, err := time.Parse(dateLayout, fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("wrong from date. Format date should be 2006-01-02")
+		}
+		from = parsed
+		to = from.AddDate(0, 0, 7)
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		parsed, err := time.Parse(dateLayout, toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("wrong to date. Format date should be 2006-01-02")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to date must not be before from date")
+	}
+	if to.Sub(from) > maxPeriodDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("period is too long")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,132 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+)
+
+const (
+	// focusBlocksLimit сколько самых длинных свободных промежутков показывать
+	focusBlocksLimit = 3
+)
+
+// interval промежуток времени [Start, End)
+type interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// computeLoad считает нагрузку пользователя по его встречам за период [from, to).
+// Пересекающиеся встречи при подсчете часов объединяются, чтобы время не учитывалось дважды.
+func computeLoad(userID uint, spans []models.MeetingSpan, eventTags map[uint][]uint, from, to time.Time) UserLoad {
+	load := UserLoad{
+		UserID:      userID,
+		Meetings:    len(spans),
+		FocusBlocks: []FocusBlock{},
+	}
+	meetings := make([]interval, 0, len(spans))
+	for _, span := range spans {
+		clipped, ok := clip(interval{Start: span.StartDate, End: span.End()}, from, to)
+		if !ok {
+			continue
+		}
+		meetings = append(meetings, clipped)
+		for _, tagID := range eventTags[span.EventID] {
+			if load.TagHours == nil {
+				load.TagHours = map[uint]float64{}
+			}
+			load.TagHours[tagID] += clipped.End.Sub(clipped.Start).Hours()
+		}
+	}
+	meetings = merge(meetings)
+	for _, m := range meetings {
+		load.MeetingHours += m.End.Sub(m.Start).Hours()
+	}
+
+	var working, busy time.Duration
+	var free []interval
+	for _, window := range workingWindows(from, to) {
+		working += window.End.Sub(window.Start)
+		cursor := window.Start
+		for _, m := range meetings {
+			overlap, ok := clip(m, window.Start, window.End)
+			if !ok {
+				continue
+			}
+			busy += overlap.End.Sub(overlap.Start)
+			if overlap.Start.After(cursor) {
+				free = append(free, interval{Start: cursor, End: overlap.Start})
+			}
+			if overlap.End.After(cursor) {
+				cursor = overlap.End
+			}
+		}
+		if window.End.After(cursor) {
+			free = append(free, interval{Start: cursor, End: window.End})
+		}
+	}
+	if working > 0 {
+		load.WorkingWeekShare = float64(busy) / float64(working)
+	}
+
+	sort.SliceStable(free, func(i, j int) bool {
+		return free[i].End.Sub(free[i].Start) > free[j].End.Sub(free[j].Start)
+	})
+	for i := 0; i < len(free) && i < focusBlocksLimit; i++ {
+		load.FocusBlocks = append(load.FocusBlocks, FocusBlock{
+			Start:   free[i].Start,
+			End:     free[i].End,
+			Minutes: int(free[i].End.Sub(free[i].Start) / time.Minute),
+		})
+	}
+	return load
+}
+
+// workingWindows возвращает рабочие часы будних дней внутри периода, по UTC
+func workingWindows(from, to time.Time) []interval {
+	var windows []interval
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		window := interval{
+			Start: day.Add(models.WorkdayStartHour * time.Hour),
+			End:   day.Add(models.WorkdayEndHour * time.Hour),
+		}
+		if clipped, ok := clip(window, from, to); ok {
+			windows = append(windows, clipped)
+		}
+	}
+	return windows
+}
+
+// merge упорядочивает промежутки по началу и объединяет пересекающиеся
+func merge(intervals []interval) []interval {
+	sort.SliceStable(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	merged := make([]interval, 0, len(intervals))
+	for _, current := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !current.Start.After(merged[last].End) {
+			if current.End.After(merged[last].End) {
+				merged[last].End = current.End
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+// clip обрезает промежуток по границам [from, to), false если пересечения нет
+func clip(i interval, from, to time.Time) (interval, bool) {
+	if i.Start.Before(from) {
+		i.Start = from
+	}
+	if i.End.After(to) {
+		i.End = to
+	}
+	return i, i.End.After(i.Start)
+}
@@ -0,0 +1,35 @@
+package analytics
+
+import "time"
+
+// FocusBlock свободный промежуток в рабочее время без встреч
+type FocusBlock struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Minutes int       `json:"minutes"`
+}
+
+// UserLoad нагрузка встречами одного пользователя за период
+type UserLoad struct {
+	UserID uint `json:"user_id"`
+	// MeetingHours часы в принятых встречах, пересечения учитываются один раз
+	MeetingHours float64 `json:"meeting_hours"`
+	Meetings     int     `json:"meetings"`
+	// WorkingWeekShare доля рабочего времени, занятая встречами, от 0 до 1
+	WorkingWeekShare float64 `json:"working_week_share"`
+	// FocusBlocks самые длинные промежутки без встреч в рабочее время
+	FocusBlocks []FocusBlock `json:"focus_blocks"`
+	// TagHours часы встреч по меткам событий
+	TagHours map[uint]float64 `json:"tag_hours,omitempty"`
+}
+
+// MeetingLoadResponse нагрузка пользователя или участников группы за период
+type MeetingLoadResponse struct {
+	From    time.Time  `json:"from"`
+	To      time.Time  `json:"to"`
+	GroupID *uint      `json:"group_id,omitempty"`
+	Users   []UserLoad `json:"users"`
+	// TotalMeetingHours и AverageShare сводка по всем пользователям ответа
+	TotalMeetingHours float64 `json:"total_meeting_hours"`
+	AverageShare      float64 `json:"average_share"`
+}
@@ -0,0 +1,41 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository struct {
+	DataBase *db.Db
+}
+
+// NewAnalyticsRepository создает новый репозиторий аналитики
+func NewAnalyticsRepository(dataBase *db.Db) *AnalyticsRepository {
+	return &AnalyticsRepository{DataBase: dataBase}
+}
+
+// AcceptedMeetings возвращает одним запросом принятые встречи пользователей,
+// пересекающиеся с периодом [from, to), упорядоченные по пользователю и времени начала.
+// Блоки фокус-времени встречами не считаются
+func (repo *AnalyticsRepository) AcceptedMeetings(userIDs []uint, from, to time.Time) ([]models.MeetingSpan, error) {
+	var spans []models.MeetingSpan
+	if len(userIDs) == 0 {
+		return spans, nil
+	}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("ep.user_id, e.id AS event_id, e.start_date, e.duration").
+		Joins("JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL AND e.focus = false").
+		Where("ep.deleted_at IS NULL AND ep.status = ? AND ep.user_id IN ?", models.StatusAccepted, userIDs).
+		Where("e.start_date < ? AND e.start_date + (e.duration || ' minutes')::interval > ?", to, from).
+		Order("ep.user_id, e.start_date").
+		Scan(&spans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return spans, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/auth"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/passwordReset"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/secret"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
@@ -36,7 +37,7 @@ func setupAuthHandler(t *testing.T) (*auth.AuthHandler, sqlmock.Sqlmock, func())
 
 	passwordReset := passwordReset.NewPasswordResetRepository(database, log)
 
-	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService)
+	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService, notifier.NewDispatcher(log))
 	handler := &auth.AuthHandler{
 		Config: &configs.Config{
 			Auth: configs.AuthConfig{
@@ -46,7 +46,11 @@ func (handler *AuthHandler) Register() http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		tokenPair, err := handler.AuthService.JWT.GenerateTokenPair(jwt.JWTData{Email: user.Email})
+		tokenPair, err := handler.AuthService.JWT.GenerateTokenPair(jwt.JWTData{
+			Email:          user.Email,
+			UserID:         user.ID,
+			OrganizationID: user.OrganizationID,
+		})
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -1,17 +1,18 @@
 package auth
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/passwordReset"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/secret"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
-	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/sendmail"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,6 +21,7 @@ type AuthService struct {
 	SecretRepository        *secret.SecretRepository
 	passwordResetRepository *passwordReset.PasswordResetRepository
 	JWT                     *jwt.JWT
+	Notifier                notifier.Notifier
 }
 
 // NewAuthService - конструктор сервиса авторизации
@@ -28,12 +30,14 @@ func NewAuthService(
 	secretRepository *secret.SecretRepository,
 	passwordResetRepository *passwordReset.PasswordResetRepository,
 	jwtService *jwt.JWT,
+	notify notifier.Notifier,
 ) *AuthService {
 	return &AuthService{
 		UserRepository:          userRepository,
 		SecretRepository:        secretRepository,
 		passwordResetRepository: passwordResetRepository,
 		JWT:                     jwtService,
+		Notifier:                notify,
 	}
 }
 
@@ -80,8 +84,9 @@ func (service *AuthService) Login(email, password string) (jwt.JWTData, error) {
 
 	// Передаём и ID, и email
 	return jwt.JWTData{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:         user.ID,
+		Email:          user.Email,
+		OrganizationID: user.OrganizationID,
 	}, nil
 }
 
@@ -101,7 +106,11 @@ func (service *AuthService) RefreshTokens(refreshToken, accessToken string) (*jw
 		return nil, errors.New(ErrUserNotFound)
 	}
 
-	tokenPair, err := service.JWT.GenerateTokenPair(jwt.JWTData{Email: existUser.Email})
+	tokenPair, err := service.JWT.GenerateTokenPair(jwt.JWTData{
+		Email:          existUser.Email,
+		UserID:         existUser.ID,
+		OrganizationID: existUser.OrganizationID,
+	})
 	if err != nil {
 		return nil, errors.New(ErrGenerateToken)
 	}
@@ -124,8 +133,9 @@ func (service *AuthService) ForgotPassword(config *configs.Config, email string)
 	if err != nil {
 		return errors.New(ErrCreatePasswordReset)
 	}
-	sendmail.SendEmailPasswordReset(config, email, token)
-	return nil
+	link := config.Public.BaseURL + "/reset-password?token=" + token
+	//без письма пользователь не сможет сбросить пароль, поэтому ошибку доставки возвращаем
+	return service.Notifier.Notify(context.Background(), notifier.PasswordReset(notifier.UserRecipient(existUser), link))
 }
 
 func (service *AuthService) ResetPassword(token, newPassword string) (*models.User, error) {
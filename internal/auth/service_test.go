@@ -9,6 +9,7 @@ import (
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/auth"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/passwordReset"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/secret"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
@@ -32,7 +33,7 @@ func setupAuthService(t *testing.T) (*auth.AuthService, sqlmock.Sqlmock, func())
 
 	passwordReset := passwordReset.NewPasswordResetRepository(database, log)
 
-	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService)
+	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService, notifier.NewDispatcher(log))
 	return authService, mockDB, cleanup
 }
 
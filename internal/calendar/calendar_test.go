@@ -0,0 +1,47 @@
+package calendar
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCalendar(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "calendars"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			uint(1), "On-call", "#ff0000", models.VisibilityShared, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	repo := NewCalendarRepository(&db.Db{DB: gormDB})
+	created, err := repo.Create(models.NewCalendar(1, "On-call", "#ff0000", "", false))
+	require.NoError(t, err, "Create calendar failed")
+	require.Equal(t, uint(1), created.ID)
+	require.False(t, created.CountsTowardBusy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCalendarKeepsFalseFlag(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "calendars" SET "color"=$1,"counts_toward_busy"=$2,"default_visibility"=$3,"name"=$4,"updated_at"=$5 WHERE id = $6 AND "calendars"."deleted_at" IS NULL`)).
+		WithArgs("#00ff00", false, models.VisibilityPrivate, "Personal", sqlmock.AnyArg(), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewCalendarRepository(&db.Db{DB: gormDB})
+	cal := models.NewCalendar(1, "Personal", "#00ff00", models.VisibilityPrivate, false)
+	cal.ID = 2
+	_, err := repo.Update(cal)
+	require.NoError(t, err, "Update calendar failed")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package calendar
+
+import (
+	"net/http"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarHandler struct {
+	CalendarRepository *CalendarRepository
+	JWTService         *jwt.JWT
+	Delegations        *delegation.DelegationRepository
+}
+
+type CalendarHandlerDeps struct {
+	CalendarRepository *CalendarRepository
+	JWTService         *jwt.JWT
+	Delegations        *delegation.DelegationRepository
+}
+
+// NewCalendarHandler регистрирует обработчики календарей
+func NewCalendarHandler(mux *chi.Mux, deps CalendarHandlerDeps) {
+	handler := &CalendarHandler{
+		CalendarRepository: deps.CalendarRepository,
+		JWTService:         deps.JWTService,
+		Delegations:        deps.Delegations,
+	}
+	mux.Handle("GET /calendars", middleware.IsAuthedAs(handler.GetCalendars(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /calendars", middleware.IsAuthedAs(handler.CreateCalendar(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /calendars/{id}", middleware.IsAuthedAs(handler.UpdateCalendar(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /calendars/{id}", middleware.IsAuthedAs(handler.DeleteCalendar(), handler.JWTService, handler.Delegations))
+}
+
+// GetCalendars возвращает календари текущего пользователя
+func (h *CalendarHandler) GetCalendars() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		calendars, err := h.CalendarRepository.FindByOwner(userID)
+		if err != nil {
+			http.Error(w, "Failed to fetch calendars", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, calendars, http.StatusOK)
+	}
+}
+
+// CreateCalendar создает календарь текущего пользователя
+func (h *CalendarHandler) CreateCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[CalendarRequest](w, r)
+		if err != nil {
+			return
+		}
+		newCalendar := models.NewCalendar(userID, body.Name, body.Color, body.DefaultVisibility, body.countsTowardBusy())
+		createdCalendar, err := h.CalendarRepository.Create(newCalendar)
+		if err != nil {
+			http.Error(w, "Not possible to create calendar", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, createdCalendar, http.StatusCreated)
+	}
+}
+
+// UpdateCalendar обновляет настройки календаря текущего пользователя
+func (h *CalendarHandler) UpdateCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		foundCalendar, ok := h.ownCalendar(w, r)
+		if !ok {
+			return
+		}
+		body, err := request.HandelBody[CalendarRequest](w, r)
+		if err != nil {
+			return
+		}
+		foundCalendar.Name = body.Name
+		foundCalendar.Color = body.Color
+		if body.DefaultVisibility != "" {
+			foundCalendar.DefaultVisibility = body.DefaultVisibility
+		}
+		foundCalendar.CountsTowardBusy = body.countsTowardBusy()
+		updatedCalendar, err := h.CalendarRepository.Update(foundCalendar)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, updatedCalendar, http.StatusOK)
+	}
+}
+
+// DeleteCalendar удаляет календарь текущего пользователя
+func (h *CalendarHandler) DeleteCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		foundCalendar, ok := h.ownCalendar(w, r)
+		if !ok {
+			return
+		}
+		if err := h.CalendarRepository.DeleteById(foundCalendar.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Calendar deleted", http.StatusOK)
+	}
+}
+
+// ownCalendar находит календарь из URL и проверяет, что он принадлежит текущему пользователю
+func (h *CalendarHandler) ownCalendar(w http.ResponseWriter, r *http.Request) (*models.Calendar, bool) {
+	userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	id, err := convert.ParseId(r, "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	foundCalendar, err := h.CalendarRepository.FindById(id)
+	if err != nil {
+		http.Error(w, "Calendar not found", http.StatusNotFound)
+		return nil, false
+	}
+	if foundCalendar.OwnerID != userID {
+		http.Error(w, "Calendar belongs to another user", http.StatusForbidden)
+		return nil, false
+	}
+	return foundCalendar, true
+}
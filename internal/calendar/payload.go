@@ -0,0 +1,17 @@
+package calendar
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// CalendarRequest данные для создания или обновления календаря
+type CalendarRequest struct {
+	Name              string                    `json:"name" validate:"required,max=100"`
+	Color             string                    `json:"color" validate:"omitempty,hexcolor"`
+	DefaultVisibility models.CalendarVisibility `json:"default_visibility" validate:"omitempty,oneof=shared private"`
+	// CountsTowardBusy по умолчанию true: события календаря делают пользователя занятым
+	CountsTowardBusy *bool `json:"counts_toward_busy"`
+}
+
+// countsTowardBusy возвращает значение флага занятости с учетом значения по умолчанию
+func (req *CalendarRequest) countsTowardBusy() bool {
+	return req.CountsTowardBusy == nil || *req.CountsTowardBusy
+}
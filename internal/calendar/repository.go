@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type CalendarRepository struct {
+	DataBase *db.Db
+}
+
+// NewCalendarRepository создает новый репозиторий календарей
+func NewCalendarRepository(dataBase *db.Db) *CalendarRepository {
+	return &CalendarRepository{DataBase: dataBase}
+}
+
+// Create создает новый календарь
+func (repo *CalendarRepository) Create(calendar *models.Calendar) (*models.Calendar, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(calendar)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendar, nil
+}
+
+// FindById находит календарь по его ID
+func (repo *CalendarRepository) FindById(id uint) (*models.Calendar, error) {
+	var calendar models.Calendar
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).First(&calendar, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &calendar, nil
+}
+
+// FindByIds находит календари по списку ID
+func (repo *CalendarRepository) FindByIds(ids []uint) ([]models.Calendar, error) {
+	var calendars []models.Calendar
+	if len(ids) == 0 {
+		return calendars, nil
+	}
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Where("id IN ?", ids).Find(&calendars)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendars, nil
+}
+
+// FindByOwner возвращает все календари пользователя
+func (repo *CalendarRepository) FindByOwner(ownerID uint) ([]models.Calendar, error) {
+	var calendars []models.Calendar
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("owner_id = ?", ownerID).
+		Order("id").
+		Find(&calendars)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendars, nil
+}
+
+// Update обновляет настройки календаря, включая нулевые значения флагов
+func (repo *CalendarRepository) Update(calendar *models.Calendar) (*models.Calendar, error) {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Calendar{}).
+		Where("id = ?", calendar.ID).
+		Updates(map[string]interface{}{
+			"name":               calendar.Name,
+			"color":              calendar.Color,
+			"default_visibility": calendar.DefaultVisibility,
+			"counts_toward_busy": calendar.CountsTowardBusy,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendar, nil
+}
+
+// DeleteById удаляет календарь, события календаря остаются без календаря
+func (repo *CalendarRepository) DeleteById(id uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("calendar_id = ?", id).Update("calendar_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Calendar{}, id).Error
+	})
+}
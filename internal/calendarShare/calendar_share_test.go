@@ -0,0 +1,59 @@
+package calendarShare
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLevelAllows(t *testing.T) {
+	require.True(t, models.ShareEdit.Allows(models.ShareDetails))
+	require.True(t, models.ShareDetails.Allows(models.ShareFreeBusy))
+	require.False(t, models.ShareFreeBusy.Allows(models.ShareDetails))
+	require.False(t, models.ShareLevel("").Allows(models.ShareFreeBusy))
+	require.Equal(t, models.ShareDetails, models.ShareFreeBusy.Max(models.ShareDetails))
+}
+
+func TestSharedOwners(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	fixedTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "calendar_shares" WHERE (grantee_user_id = $1 OR grantee_group_id IN (SELECT group_id FROM group_members WHERE user_id = $2 AND deleted_at IS NULL)) AND owner_id <> $3 AND "calendar_shares"."deleted_at" IS NULL`)).
+		WithArgs(uint(3), uint(3), uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "owner_id", "grantee_user_id", "grantee_group_id", "level"}).
+			AddRow(1, fixedTime, fixedTime, nil, 1, 3, nil, models.ShareFreeBusy).
+			AddRow(2, fixedTime, fixedTime, nil, 2, 3, nil, models.ShareEdit).
+			AddRow(3, fixedTime, fixedTime, nil, 1, nil, 7, models.ShareDetails))
+
+	repo := NewCalendarShareRepository(&db.Db{DB: gormDB})
+	owners, err := repo.SharedOwners(3)
+	require.NoError(t, err)
+	//доступ через группу расширяет личный доступ к тому же календарю
+	require.Equal(t, models.ShareDetails, owners[1])
+	require.Equal(t, models.ShareEdit, owners[2])
+	require.Equal(t, models.ShareLevel(""), owners[4])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokeShare(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "calendar_shares" SET "deleted_at"=$1 WHERE (id = $2 AND owner_id = $3) AND "calendar_shares"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), uint(5), uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewCalendarShareRepository(&db.Db{DB: gormDB})
+	err := repo.Revoke(5, 1)
+	require.Error(t, err, "Revoke of foreign share should fail")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
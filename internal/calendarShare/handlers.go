@@ -0,0 +1,128 @@
+package calendarShare
+
+import (
+	"net/http"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+type CalendarShareHandler struct {
+	CalendarShareRepository *CalendarShareRepository
+	UserRepository          *user.UserRepository
+	GroupRepository         *group.GroupRepository
+	JWTService              *jwt.JWT
+}
+
+type CalendarShareHandlerDeps struct {
+	CalendarShareRepository *CalendarShareRepository
+	UserRepository          *user.UserRepository
+	GroupRepository         *group.GroupRepository
+	JWTService              *jwt.JWT
+}
+
+// NewCalendarShareHandler регистрирует обработчики доступа к календарям
+func NewCalendarShareHandler(mux *chi.Mux, deps CalendarShareHandlerDeps) {
+	handler := &CalendarShareHandler{
+		CalendarShareRepository: deps.CalendarShareRepository,
+		UserRepository:          deps.UserRepository,
+		GroupRepository:         deps.GroupRepository,
+		JWTService:              deps.JWTService,
+	}
+	mux.Handle("GET /calendar/shares", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("POST /calendar/shares", middleware.IsAuthed(handler.Grant(), handler.JWTService))
+	mux.Handle("DELETE /calendar/shares/{id}", middleware.IsAuthed(handler.Revoke(), handler.JWTService))
+}
+
+// List возвращает выданные и полученные доступы текущего пользователя
+func (h *CalendarShareHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		granted, err := h.CalendarShareRepository.FindByOwner(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		received, err := h.CalendarShareRepository.FindReceived(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, SharesResponse{
+			Granted:  granted,
+			Received: received,
+		}, http.StatusOK)
+	}
+}
+
+// Grant открывает календарь текущего пользователя другому пользователю или группе
+func (h *CalendarShareHandler) Grant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[GrantRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		var share *models.CalendarShare
+		if body.UserID != nil {
+			if *body.UserID == ownerID {
+				http.Error(w, "Can not share calendar with yourself", http.StatusBadRequest)
+				return
+			}
+			if _, err := h.UserRepository.FindInOrganization(*body.UserID, orgID); err != nil {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+			share, err = h.CalendarShareRepository.GrantToUser(ownerID, *body.UserID, body.Level)
+		} else {
+			foundGroup, findErr := h.GroupRepository.FindById(*body.GroupID)
+			if findErr != nil || foundGroup.OrganizationID != orgID {
+				http.Error(w, "Group not found", http.StatusNotFound)
+				return
+			}
+			share, err = h.CalendarShareRepository.GrantToGroup(ownerID, *body.GroupID, body.Level)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, share, http.StatusCreated)
+	}
+}
+
+// Revoke закрывает доступ к календарю текущего пользователя
+func (h *CalendarShareHandler) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.CalendarShareRepository.Revoke(id, ownerID); err != nil {
+			http.Error(w, "Share not found", http.StatusNotFound)
+			return
+		}
+		res.JsonResponse(w, "Share revoked", http.StatusOK)
+	}
+}
@@ -0,0 +1,16 @@
+package calendarShare
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// GrantRequest данные для открытия календаря: указывается пользователь или группа
+type GrantRequest struct {
+	UserID  *uint             `json:"user_id" validate:"required_without=GroupID,excluded_with=GroupID"`
+	GroupID *uint             `json:"group_id" validate:"required_without=UserID"`
+	Level   models.ShareLevel `json:"level" validate:"required,oneof=free_busy details edit"`
+}
+
+// SharesResponse доступы, выданные пользователем и полученные им
+type SharesResponse struct {
+	Granted  []models.CalendarShare `json:"granted"`
+	Received []models.CalendarShare `json:"received"`
+}
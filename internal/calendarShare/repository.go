@@ -0,0 +1,115 @@
+package calendarShare
+
+import (
+	"errors"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type CalendarShareRepository struct {
+	DataBase *db.Db
+}
+
+// NewCalendarShareRepository создает новый репозиторий доступа к календарям
+func NewCalendarShareRepository(dataBase *db.Db) *CalendarShareRepository {
+	return &CalendarShareRepository{DataBase: dataBase}
+}
+
+// GrantToUser открывает календарь владельца пользователю или меняет уровень существующего доступа
+func (repo *CalendarShareRepository) GrantToUser(ownerID, granteeUserID uint, level models.ShareLevel) (*models.CalendarShare, error) {
+	return repo.grant("grantee_user_id", ownerID, granteeUserID, models.NewUserCalendarShare(ownerID, granteeUserID, level))
+}
+
+// GrantToGroup открывает календарь владельца участникам группы или меняет уровень существующего доступа
+func (repo *CalendarShareRepository) GrantToGroup(ownerID, granteeGroupID uint, level models.ShareLevel) (*models.CalendarShare, error) {
+	return repo.grant("grantee_group_id", ownerID, granteeGroupID, models.NewGroupCalendarShare(ownerID, granteeGroupID, level))
+}
+
+// grant обновляет уровень доступа, уже выданного получателю, или сохраняет новый доступ
+func (repo *CalendarShareRepository) grant(granteeColumn string, ownerID, granteeID uint, newShare *models.CalendarShare) (*models.CalendarShare, error) {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	var share models.CalendarShare
+	err := db.Where("owner_id = ? AND "+granteeColumn+" = ?", ownerID, granteeID).First(&share).Error
+	if err == nil {
+		share.Level = newShare.Level
+		if err := db.Model(&share).Update("level", newShare.Level).Error; err != nil {
+			return nil, err
+		}
+		return &share, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := db.Create(newShare).Error; err != nil {
+		return nil, err
+	}
+	return newShare, nil
+}
+
+// FindByOwner возвращает доступы, выданные владельцем календаря
+func (repo *CalendarShareRepository) FindByOwner(ownerID uint) ([]models.CalendarShare, error) {
+	var shares []models.CalendarShare
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("owner_id = ?", ownerID).
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// FindReceived возвращает доступы к чужим календарям, выданные пользователю
+// напрямую и через группы, в которых он состоит
+func (repo *CalendarShareRepository) FindReceived(userID uint) ([]models.CalendarShare, error) {
+	var shares []models.CalendarShare
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("grantee_user_id = ? OR grantee_group_id IN (SELECT group_id FROM group_members WHERE user_id = ? AND deleted_at IS NULL)", userID, userID).
+		Where("owner_id <> ?", userID).
+		Find(&shares).Error
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// Revoke закрывает доступ, удалить его может только владелец календаря
+func (repo *CalendarShareRepository) Revoke(id, ownerID uint) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ? AND owner_id = ?", id, ownerID).
+		Delete(&models.CalendarShare{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// LevelFor возвращает уровень доступа пользователя к календарю владельца, пустая строка — доступа нет
+func (repo *CalendarShareRepository) LevelFor(ownerID, viewerID uint) (models.ShareLevel, error) {
+	levels, err := repo.SharedOwners(viewerID)
+	if err != nil {
+		return "", err
+	}
+	return levels[ownerID], nil
+}
+
+// SharedOwners возвращает владельцев календарей, открытых пользователю лично или через группы,
+// и наибольший уровень доступа к каждому
+func (repo *CalendarShareRepository) SharedOwners(viewerID uint) (map[uint]models.ShareLevel, error) {
+	shares, err := repo.FindReceived(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	levels := make(map[uint]models.ShareLevel, len(shares))
+	for _, share := range shares {
+		levels[share.OwnerID] = levels[share.OwnerID].Max(share.Level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,45 @@
+package delegation
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelegationLevelAllows(t *testing.T) {
+	require.True(t, models.DelegationManage.Allows(models.DelegationWrite))
+	require.True(t, models.DelegationWrite.Allows(models.DelegationRead))
+	require.False(t, models.DelegationRead.Allows(models.DelegationWrite))
+	require.False(t, models.DelegationLevel("admin").Allows(models.DelegationRead))
+}
+
+func TestCanActFor(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	fixedTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT * FROM "delegations" WHERE (owner_id = $1 AND delegate_id = $2) AND "delegations"."deleted_at" IS NULL ORDER BY "delegations"."id" LIMIT $3`)
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "owner_id", "delegate_id", "level"}).
+			AddRow(1, fixedTime, fixedTime, nil, 7, 42, models.DelegationWrite)
+	}
+	mock.ExpectQuery(query).WithArgs(uint(7), uint(42), 1).WillReturnRows(rows())
+	mock.ExpectQuery(query).WithArgs(uint(7), uint(42), 1).WillReturnRows(rows())
+
+	repo := NewDelegationRepository(&db.Db{DB: gormDB})
+
+	canWrite, err := repo.CanActFor(7, 42, http.MethodPut)
+	require.NoError(t, err)
+	require.True(t, canWrite, "write delegation should allow PUT")
+
+	canDelete, err := repo.CanActFor(7, 42, http.MethodDelete)
+	require.NoError(t, err)
+	require.False(t, canDelete, "write delegation should not allow DELETE")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,152 @@
+package delegation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+type DelegationHandler struct {
+	DelegationRepository *DelegationRepository
+	UserRepository       *user.UserRepository
+	JWTService           *jwt.JWT
+}
+
+type DelegationHandlerDeps struct {
+	DelegationRepository *DelegationRepository
+	UserRepository       *user.UserRepository
+	JWTService           *jwt.JWT
+}
+
+// NewDelegationHandler регистрирует обработчики делегирования.
+// Управлять разрешениями может только сам владелец, поэтому здесь не используется IsAuthedAs.
+func NewDelegationHandler(mux *chi.Mux, deps DelegationHandlerDeps) {
+	handler := &DelegationHandler{
+		DelegationRepository: deps.DelegationRepository,
+		UserRepository:       deps.UserRepository,
+		JWTService:           deps.JWTService,
+	}
+	mux.Handle("POST /delegations", middleware.IsAuthed(handler.Grant(), handler.JWTService))
+	mux.Handle("GET /delegations", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("DELETE /delegations/{id}", middleware.IsAuthed(handler.Revoke(), handler.JWTService))
+	mux.Handle("GET /delegations/audit", middleware.IsAuthed(handler.Audit(), handler.JWTService))
+}
+
+// Grant выдает помощнику доступ к календарю текущего пользователя
+func (h *DelegationHandler) Grant() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[GrantRequest](w, r)
+		if err != nil {
+			return
+		}
+		if body.DelegateID == ownerID {
+			http.Error(w, "Can not delegate to yourself", http.StatusBadRequest)
+			return
+		}
+		//делегировать можно только пользователю своей организации
+		orgID, _ := middleware.OrganizationID(r.Context())
+		if _, err := h.UserRepository.FindInOrganization(body.DelegateID, orgID); err != nil {
+			http.Error(w, "Delegate not found", http.StatusNotFound)
+			return
+		}
+		delegation, err := h.DelegationRepository.Grant(ownerID, body.DelegateID, body.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, delegation, http.StatusCreated)
+	}
+}
+
+// List возвращает выданные и полученные разрешения текущего пользователя
+func (h *DelegationHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		granted, err := h.DelegationRepository.FindByOwner(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		received, err := h.DelegationRepository.FindByDelegate(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, DelegationsResponse{
+			Granted:  granted,
+			Received: received,
+		}, http.StatusOK)
+	}
+}
+
+// Revoke отзывает разрешение, выданное текущим пользователем
+func (h *DelegationHandler) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ownerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.DelegationRepository.Revoke(id, ownerID); err != nil {
+			http.Error(w, "Delegation not found", http.StatusNotFound)
+			return
+		}
+		res.JsonResponse(w, "Delegation revoked", http.StatusOK)
+	}
+}
+
+// Audit возвращает журнал действий, выполненных помощниками от имени текущего пользователя
+// и самим пользователем от имени других
+func (h *DelegationHandler) Audit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		limit, offset := 20, 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limitInt, err := strconv.Atoi(limitStr)
+			if err != nil || limitInt < 1 || limitInt > 100 {
+				http.Error(w, "Invalid limit param, max 100 value", http.StatusBadRequest)
+				return
+			}
+			limit = limitInt
+		}
+		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+			offsetInt, err := strconv.Atoi(offsetStr)
+			if err != nil || offsetInt < 0 {
+				http.Error(w, "Invalid offset param, min 0 value", http.StatusBadRequest)
+				return
+			}
+			offset = offsetInt
+		}
+		audits, err := h.DelegationRepository.FindAuditByOwner(userID, limit, offset)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, audits, http.StatusOK)
+	}
+}
@@ -0,0 +1,15 @@
+package delegation
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// GrantRequest данные для выдачи доступа помощнику
+type GrantRequest struct {
+	DelegateID uint                   `json:"delegate_id" validate:"required"`
+	Level      models.DelegationLevel `json:"level" validate:"required,oneof=read write manage"`
+}
+
+// DelegationsResponse разрешения, выданные пользователем и полученные им
+type DelegationsResponse struct {
+	Granted  []models.Delegation `json:"granted"`
+	Received []models.Delegation `json:"received"`
+}
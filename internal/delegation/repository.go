@@ -0,0 +1,137 @@
+package delegation
+
+import (
+	"errors"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+type DelegationRepository struct {
+	DataBase *db.Db
+}
+
+// Убедимся, что DelegationRepository подходит для middleware.ActAs
+var _ middleware.DelegationChecker = (*DelegationRepository)(nil)
+
+// NewDelegationRepository создает новый репозиторий делегирования
+func NewDelegationRepository(dataBase *db.Db) *DelegationRepository {
+	return &DelegationRepository{DataBase: dataBase}
+}
+
+// Grant выдает помощнику доступ к календарю владельца или меняет уровень существующего доступа
+func (repo *DelegationRepository) Grant(ownerID, delegateID uint, level models.DelegationLevel) (*models.Delegation, error) {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	existing, err := repo.Find(ownerID, delegateID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		existing.Level = level
+		if err := db.Model(existing).Update("level", level).Error; err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	delegation := models.NewDelegation(ownerID, delegateID, level)
+	if err := db.Create(delegation).Error; err != nil {
+		return nil, err
+	}
+	return delegation, nil
+}
+
+// Find ищет разрешение владельца для помощника, возвращает nil если его нет
+func (repo *DelegationRepository) Find(ownerID, delegateID uint) (*models.Delegation, error) {
+	var delegation models.Delegation
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("owner_id = ? AND delegate_id = ?", ownerID, delegateID).
+		First(&delegation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// FindByOwner возвращает разрешения, выданные владельцем
+func (repo *DelegationRepository) FindByOwner(ownerID uint) ([]models.Delegation, error) {
+	var delegations []models.Delegation
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("owner_id = ?", ownerID).
+		Find(&delegations).Error
+	if err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// FindByDelegate возвращает разрешения, выданные помощнику
+func (repo *DelegationRepository) FindByDelegate(delegateID uint) ([]models.Delegation, error) {
+	var delegations []models.Delegation
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("delegate_id = ?", delegateID).
+		Find(&delegations).Error
+	if err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// Revoke отзывает разрешение, удалить его может только владелец
+func (repo *DelegationRepository) Revoke(id, ownerID uint) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ? AND owner_id = ?", id, ownerID).
+		Delete(&models.Delegation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CanActFor проверяет, может ли помощник выполнить HTTP-метод от имени владельца
+func (repo *DelegationRepository) CanActFor(ownerID, delegateID uint, method string) (bool, error) {
+	delegation, err := repo.Find(ownerID, delegateID)
+	if err != nil || delegation == nil {
+		return false, err
+	}
+	return delegation.Level.Allows(models.DelegationLevelForMethod(method)), nil
+}
+
+// RecordDelegatedAction сохраняет в журнал действие помощника и владельца, от имени которого оно выполнено
+func (repo *DelegationRepository) RecordDelegatedAction(actorID, ownerID uint, method, path string, status int) error {
+	audit := &models.DelegationAudit{
+		ActorID: actorID,
+		OwnerID: ownerID,
+		Method:  method,
+		Path:    path,
+		Status:  status,
+	}
+	return repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(audit).Error
+}
+
+// FindAuditByOwner возвращает журнал действий, выполненных от имени владельца
+func (repo *DelegationRepository) FindAuditByOwner(ownerID uint, limit, offset int) ([]models.DelegationAudit, error) {
+	var audits []models.DelegationAudit
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("owner_id = ? OR actor_id = ?", ownerID, ownerID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&audits).Error
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
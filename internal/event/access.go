@@ -0,0 +1,111 @@
+package event
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// eventAccess вычисляет уровень доступа пользователя к каждому событию из списка.
+// Создатель может редактировать свое событие, участник видит его целиком, остальные —
+// в пределах доступа к календарю создателя или участника. Доступ через календарь участника
+// не дает права редактировать чужое событие, а события приватных календарей посторонним видны
+// только как занятость. Событие без доступа в результат не попадает.
+func (h *EventHandler) eventAccess(events []models.Event, userID uint) (map[uint]models.ShareLevel, error) {
+	levels := make(map[uint]models.ShareLevel, len(events))
+	if len(events) == 0 {
+		return levels, nil
+	}
+	sharedOwners, err := h.CalendarShares.SharedOwners(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventIDs := make([]uint, 0, len(events))
+	for _, ev := range events {
+		eventIDs = append(eventIDs, ev.ID)
+	}
+	userIDs := []uint{userID}
+	for ownerID := range sharedOwners {
+		userIDs = append(userIDs, ownerID)
+	}
+	participations, err := h.EventParticipant.FindParticipations(eventIDs, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	//события из приватных календарей посторонние видят только как занятость
+	privateEvents, err := h.privateCalendarEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		switch {
+		case ev.CreatorID == userID:
+			levels[ev.ID] = models.ShareEdit
+		case sharedOwners[ev.CreatorID] != "":
+			levels[ev.ID] = sharedOwners[ev.CreatorID]
+			if privateEvents[ev.ID] {
+				levels[ev.ID] = levels[ev.ID].Cap(models.ShareFreeBusy)
+			}
+		}
+	}
+	for _, p := range participations {
+		level := models.ShareDetails
+		if p.UserID != userID {
+			level = sharedOwners[p.UserID].Cap(models.ShareDetails)
+			if privateEvents[p.EventID] {
+				level = level.Cap(models.ShareFreeBusy)
+			}
+		}
+		levels[p.EventID] = levels[p.EventID].Max(level)
+	}
+	return levels, nil
+}
+
+// privateCalendarEvents отмечает события, относящиеся к приватным календарям
+func (h *EventHandler) privateCalendarEvents(events []models.Event) (map[uint]bool, error) {
+	calendarIDs := make([]uint, 0, len(events))
+	for _, ev := range events {
+		if ev.CalendarID != nil {
+			calendarIDs = append(calendarIDs, *ev.CalendarID)
+		}
+	}
+	calendars, err := h.Calendars.FindByIds(calendarIDs)
+	if err != nil {
+		return nil, err
+	}
+	private := make(map[uint]bool, len(calendars))
+	for _, c := range calendars {
+		private[c.ID] = c.DefaultVisibility == models.VisibilityPrivate
+	}
+	privateEvents := make(map[uint]bool)
+	for _, ev := range events {
+		if ev.CalendarID != nil && private[*ev.CalendarID] {
+			privateEvents[ev.ID] = true
+		}
+	}
+	return privateEvents, nil
+}
+
+// sharedOwnerIDs возвращает владельцев календарей, открытых пользователю
+func (h *EventHandler) sharedOwnerIDs(userID uint) ([]uint, error) {
+	sharedOwners, err := h.CalendarShares.SharedOwners(userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerIDs := make([]uint, 0, len(sharedOwners))
+	for ownerID := range sharedOwners {
+		ownerIDs = append(ownerIDs, ownerID)
+	}
+	return ownerIDs, nil
+}
+
+// canManage проверяет, что пользователь организатор события или ему открыт календарь создателя на редактирование
+func (h *EventHandler) canManage(ev *models.Event, userID uint) (bool, error) {
+	canManage, err := h.EventParticipant.CanManageEvent(ev.ID, userID)
+	if err != nil || canManage {
+		return canManage, err
+	}
+	shareLevel, err := h.CalendarShares.LevelFor(ev.CreatorID, userID)
+	if err != nil {
+		return false, err
+	}
+	return shareLevel.Allows(models.ShareEdit), nil
+}
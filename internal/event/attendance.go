@@ -0,0 +1,259 @@
+package event
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"gorm.io/gorm"
+)
+
+// checkInOpensBefore за сколько до начала встречи открывается отметка участников
+const checkInOpensBefore = 15 * time.Minute
+
+// CheckIn отмечает присутствие участника. Отметиться можно с открытия окна до окончания встречи.
+func (h *EventHandler) CheckIn() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		now := time.Now().UTC()
+		end := foundEvent.StartDate.Add(time.Duration(foundEvent.Duration) * time.Minute)
+		if now.Before(foundEvent.StartDate.Add(-checkInOpensBefore)) || now.After(end) {
+			http.Error(w, "Check-in is closed", http.StatusConflict)
+			return
+		}
+		err = h.EventParticipant.CheckIn(foundEvent.ID, userId, now)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "You are not a participant of the event", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Checked in", http.StatusOK)
+	}
+}
+
+// MarkAttendance отметка организатора о присутствии участника, доступна после начала встречи
+func (h *EventHandler) MarkAttendance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		participantId, err := convert.ParseId(r, "user_id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, err := request.HandelBody[AttendanceRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		canManage, err := h.canManage(foundEvent, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !canManage {
+			http.Error(w, "Only organizers can mark attendance", http.StatusForbidden)
+			return
+		}
+		if time.Now().UTC().Before(foundEvent.StartDate) {
+			http.Error(w, "Event has not started yet", http.StatusConflict)
+			return
+		}
+		err = h.EventParticipant.MarkAttendance(foundEvent.ID, participantId, body.Attended)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Participant not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Attendance marked", http.StatusOK)
+	}
+}
+
+// EventAttendance возвращает присутствие участников события и долю пришедших.
+// Сводка и неявки считаются только после окончания встречи.
+func (h *EventHandler) EventAttendance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		access, err := h.eventAccess([]models.Event{*foundEvent}, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !access[foundEvent.ID].Allows(models.ShareDetails) {
+			http.Error(w, "Event not available", http.StatusForbidden)
+			return
+		}
+		summaries, err := h.EventParticipant.AttendanceSummaries([]uint{foundEvent.ID}, time.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		participants, err := h.EventParticipant.EventAttendance(foundEvent.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := EventAttendanceResponse{Participants: participants}
+		response.EventID = foundEvent.ID
+		if len(summaries) > 0 {
+			response.AttendanceSummary = summaries[0]
+		}
+		response.Rate = response.AttendanceSummary.Rate()
+		res.JsonResponse(w, response, http.StatusOK)
+	}
+}
+
+// SeriesAttendance возвращает присутствие по прошедшим событиям серии, доступным пользователю
+func (h *EventHandler) SeriesAttendance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		seriesId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		events, err := h.EventRepository.FindSeries(seriesId, orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(events) == 0 {
+			http.Error(w, "Series not found", http.StatusNotFound)
+			return
+		}
+		access, err := h.eventAccess(events, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		now := time.Now().UTC()
+		eventIDs := make([]uint, 0, len(events))
+		for i := range events {
+			if access[events[i].ID].Allows(models.ShareDetails) && ended(&events[i], now) {
+				eventIDs = append(eventIDs, events[i].ID)
+			}
+		}
+		summaries, err := h.EventParticipant.AttendanceSummaries(eventIDs, now)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := SeriesAttendanceResponse{SeriesID: seriesId, Events: summaries}
+		total := models.AttendanceSummary{}
+		for _, s := range summaries {
+			total.Accepted += s.Accepted
+			total.Attended += s.Attended
+			total.NoShows += s.NoShows
+		}
+		response.Accepted = total.Accepted
+		response.Attended = total.Attended
+		response.NoShows = total.NoShows
+		response.Rate = total.Rate()
+		res.JsonResponse(w, response, http.StatusOK)
+	}
+}
+
+// UserAttendance возвращает неявки пользователя на прошедшие встречи: свои или, для администратора,
+// любого пользователя организации
+func (h *EventHandler) UserAttendance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		targetId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if targetId != userId {
+			currentUser, err := h.UserRepository.FindByid(userId)
+			if err != nil {
+				http.Error(w, "User not found", http.StatusUnauthorized)
+				return
+			}
+			if currentUser.OrganizationID == models.DefaultOrganizationID || currentUser.OrgRole != models.OrgRoleAdmin {
+				http.Error(w, "Only organization admins can view other users", http.StatusForbidden)
+				return
+			}
+			if _, err := h.UserRepository.FindInOrganization(targetId, currentUser.OrganizationID); err != nil {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+		}
+		attendance, err := h.EventParticipant.UserAttendance(targetId, time.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if attendance.Accepted > 0 {
+			attendance.Rate = float64(attendance.Attended) / float64(attendance.Accepted)
+		}
+		res.JsonResponse(w, attendance, http.StatusOK)
+	}
+}
+
+// ended сообщает, закончилась ли встреча к моменту now
+func ended(ev *models.Event, now time.Time) bool {
+	return !now.Before(ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute))
+}
@@ -1,15 +1,35 @@
 package event
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendarShare"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventGuest"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventParticipant"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/outOfOffice"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/rsvp"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestCreateEvent(t *testing.T) {
@@ -153,8 +173,8 @@ func TestGetEventWithCreator(t *testing.T) {
 	require.NoError(t, err)
 
 	// Мокаем запрос на получение события
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE (id = $1 AND creator_id = $2) AND "events"."deleted_at" IS NULL ORDER BY "events"."id" LIMIT $3`)).
-		WithArgs(uint(1), uint(1), uint(1)).
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE (id = $1 AND creator_id = $2 AND organization_id = $3) AND "events"."deleted_at" IS NULL ORDER BY "events"."id" LIMIT $4`)).
+		WithArgs(uint(1), uint(1), uint(2), 1).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "created_at", "updated_at", "deleted_at", "title", "description", "event_date", "creator_id",
 		}).AddRow(1, fixedTime, fixedTime, nil, "testevent", "description", date, 1))
@@ -169,7 +189,7 @@ func TestGetEventWithCreator(t *testing.T) {
 	dbWrapper := &db.Db{DB: gormDB}
 	repo := NewEventRepository(dbWrapper)
 	repo.DataBase.DB = repo.DataBase.DB.Model(&models.Event{}) // Установка модели таблицы
-	event, err := repo.GetEventWithCreator(uint(1), uint(1))
+	event, err := repo.GetEventWithCreator(uint(1), uint(1), uint(2))
 	require.NoError(t, err, "Error getting event with creator")
 	require.NotNil(t, event, "Event should not be nil")
 	require.Equal(t, uint(1), event.ID)
@@ -177,3 +197,581 @@ func TestGetEventWithCreator(t *testing.T) {
 	require.Equal(t, uint(1), event.CreatorID)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateIfVersionConflict(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "updated_at"=$1,"title"=$2,"description"=$3,"agenda"=$4,"minutes"=$5,"start_date"=$6,"duration"=$7,"conference_link"=$8,"calendar_id"=$9,"version"=$10 WHERE version = $11 AND "events"."deleted_at" IS NULL AND "id" = $12`)).
+		WithArgs(sqlmock.AnyArg(), "newtestevent", "", "", "", sqlmock.AnyArg(), 30, "", nil, 3, 2, uint(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewEventRepository(&db.Db{DB: gormDB})
+	event := &models.Event{Title: "newtestevent", Duration: 30, Version: 2}
+	event.ID = 1
+	_, err := repo.UpdateIfVersion(event, 2)
+	require.ErrorIs(t, err, ErrVersionConflict)
+	require.Equal(t, 2, event.Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteIfVersion(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "conference_link"=$1,"deleted_at"=$2,"updated_at"=$3 WHERE (id = $4 AND version = $5) AND "events"."deleted_at" IS NULL`)).
+		WithArgs("", sqlmock.AnyArg(), sqlmock.AnyArg(), uint(1), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewEventRepository(&db.Db{DB: gormDB})
+	require.NoError(t, repo.DeleteIfVersion(1, 4))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBumpVersion(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "version"=version + 1,"updated_at"=$1 WHERE id = $2 AND "events"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), uint(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewEventRepository(&db.Db{DB: gormDB})
+	require.NoError(t, repo.BumpVersion(10))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchEvents(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectQuery(`SELECT events\.\*, ts_rank\(events\.search_vector, q\.ru \|\| q\.en\) AS rank, CASE .+ END AS headline FROM "events" `+
+		`CROSS JOIN \(SELECT websearch_to_tsquery\('russian', \$1\) AS ru, websearch_to_tsquery\('english', \$2\) AS en\) q `+
+		`WHERE .+c\.default_visibility = \$\d+\)\)\) AND events\.search_vector @@ \(q\.ru \|\| q\.en\) `+
+		`ORDER BY rank DESC, events\.start_date DESC LIMIT \$\d+`).
+		WithArgs("бюджет Q3", "бюджет Q3", uint(0), uint(1), uint(1), uint(2), uint(2), models.VisibilityPrivate, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "rank", "headline"}).
+			AddRow(5, "Бюджет на Q3", 0.6, "<mark>Бюджет</mark> на <mark>Q3</mark>"))
+
+	repo := NewEventRepository(&db.Db{DB: gormDB})
+	hits, err := repo.Search(1, 0, []uint{2}, "бюджет Q3", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, uint(5), hits[0].ID)
+	require.Equal(t, "Бюджет на Q3", hits[0].Title)
+	require.InDelta(t, 0.6, hits[0].Rank, 0.001)
+	require.Contains(t, hits[0].Headline, "<mark>")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamAttendance(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	responded := start.Add(-time.Hour)
+
+	mock.ExpectQuery(`SELECT e\.id AS event_id, e\.title, .+ FROM events e JOIN users creator .+ LEFT JOIN event_participants ep .+ `+
+		`WHERE e\.id IN \(\$1,\$2\) AND e\.deleted_at IS NULL ORDER BY e\.start_date, e\.id, u\.username`).
+		WithArgs(uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "title", "start_date", "duration", "organizer", "participant",
+			"participant_email", "role", "status", "responded_at"}).
+			AddRow(1, "Бюджет", start, 30, "Test1", "Test1", "test1@test1.ru", "organizer", "Принято", nil).
+			AddRow(1, "Бюджет", start, 30, "Test1", "Test2", "test2@test2.ru", "required", "Отклонено", responded))
+
+	repo := NewEventRepository(&db.Db{DB: gormDB})
+	var buf strings.Builder
+	out := &csvRows{csv.NewWriter(&buf)}
+	err := repo.StreamAttendance([]uint{1, 2}, func(row *models.AttendanceRow) error {
+		var respondedAt any
+		if row.RespondedAt != nil {
+			respondedAt = *row.RespondedAt
+		}
+		return out.WriteRow(row.Title, row.StartDate, row.Duration, row.Participant, string(row.Status), respondedAt)
+	})
+	require.NoError(t, err)
+	require.NoError(t, out.Close())
+	require.Equal(t, "Бюджет,2025-06-02 10:00,30,Test1,Принято,\n"+
+		"Бюджет,2025-06-02 10:00,30,Test2,Отклонено,2025-06-02 09:00\n", buf.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCSVRowsEscapeFormulas(t *testing.T) {
+	var buf strings.Builder
+	out := &csvRows{csv.NewWriter(&buf)}
+	require.NoError(t, out.WriteRow("=HYPERLINK(\"http://evil\")", "+1", "-1", "@SUM(A1)", "Бюджет", 30))
+	require.NoError(t, out.Close())
+	require.Equal(t, "\"'=HYPERLINK(\"\"http://evil\"\")\",'+1,'-1,'@SUM(A1),Бюджет,30\n", buf.String())
+}
+
+func TestRSVPGuestAccept(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+	start := time.Now().UTC().Add(24 * time.Hour)
+
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE "events"."id" = \$1`).
+		WithArgs(uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "start_date", "duration"}).AddRow(7, "Демо для клиента", start, 60))
+	mock.ExpectQuery(`SELECT "event_guests"\."id".* FROM "event_guests" JOIN events`).
+		WithArgs(uint(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "email"}).AddRow(3, 7, "client@example.com"))
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE "events"."id" = \$1`).
+		WithArgs(uint(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(7, "Демо для клиента"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "event_guests" SET "responded_at"=\$1,"status"=\$2,"updated_at"=\$3 WHERE id = \$4`).
+		WithArgs(sqlmock.AnyArg(), models.StatusAccepted, sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	handler := &EventHandler{
+		EventRepository: NewEventRepository(database),
+		Guests:          eventGuest.NewEventGuestRepository(database),
+		Config: &configs.Config{
+			Auth:   configs.AuthConfig{Secret: "secret"},
+			Public: configs.PublicConfig{BaseURL: "https://meeting.example.com"},
+		},
+	}
+	router := chi.NewRouter()
+	router.HandleFunc("GET /rsvp/{token}", handler.RSVP())
+
+	accept, _ := handler.rsvpLinks(&models.Event{Model: gorm.Model{ID: 7}, StartDate: start, Duration: 60}, 0, 3)
+	require.True(t, strings.HasPrefix(accept, "https://meeting.example.com/rsvp/"))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, strings.TrimPrefix(accept, "https://meeting.example.com"), nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `<html lang="en">`)
+	require.Contains(t, rec.Body.String(), "Invitation accepted")
+	require.Contains(t, rec.Body.String(), "Демо для клиента")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRSVPRejectsBadLinks(t *testing.T) {
+	handler := &EventHandler{Config: &configs.Config{Auth: configs.AuthConfig{Secret: "secret"}}}
+	router := chi.NewRouter()
+	router.HandleFunc("GET /rsvp/{token}", handler.RSVP())
+
+	key := rsvp.DeriveKey("secret")
+	expired := rsvp.Sign(key, rsvp.Claims{EventID: 7, UserID: 1, Action: rsvp.ActionAccept, Expires: time.Now().Add(-time.Hour)})
+	forged := rsvp.Sign("other", rsvp.Claims{EventID: 7, UserID: 1, Action: rsvp.ActionAccept, Expires: time.Now().Add(time.Hour)})
+	//ссылки подписываются выведенным ключом, а не секретом авторизации
+	authSigned := rsvp.Sign("secret", rsvp.Claims{EventID: 7, UserID: 1, Action: rsvp.ActionAccept, Expires: time.Now().Add(time.Hour)})
+	cases := map[string]int{
+		expired:    http.StatusGone,
+		forged:     http.StatusBadRequest,
+		authSigned: http.StatusBadRequest,
+	}
+	for token, code := range cases {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rsvp/"+token, nil))
+		require.Equal(t, code, rec.Code)
+		require.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+		require.Contains(t, rec.Body.String(), `<html lang="ru">`)
+	}
+}
+
+func TestRSVPRejectsLinkBeforeReschedule(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+	oldStart := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE "events"."id" = \$1`).
+		WithArgs(uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "start_date", "duration"}).
+			AddRow(7, "Демо для клиента", oldStart.Add(2*time.Hour), 60))
+
+	handler := &EventHandler{
+		EventRepository: NewEventRepository(database),
+		Config:          &configs.Config{Auth: configs.AuthConfig{Secret: "secret"}},
+	}
+	router := chi.NewRouter()
+	router.HandleFunc("GET /rsvp/{token}", handler.RSVP())
+
+	//ссылка выпущена до переноса встречи на два часа
+	accept, _ := handler.rsvpLinks(&models.Event{Model: gorm.Model{ID: 7}, StartDate: oldStart, Duration: 60}, 0, 3)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(accept, handler.Config.Public.BaseURL), nil))
+	require.Equal(t, http.StatusGone, rec.Code)
+	require.Contains(t, rec.Body.String(), "Встреча перенесена")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimGuestInvitesRequiresSameEmail(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	mock.ExpectQuery(`SELECT "event_guests"\."id".* FROM "event_guests" JOIN events`).
+		WithArgs(uint(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "email"}).AddRow(3, 7, "client@example.com"))
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE "events"."id" = \$1`).
+		WithArgs(uint(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(7, "Демо для клиента"))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+		WithArgs(uint(9), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(9, "attacker@example.com"))
+
+	handler := &EventHandler{
+		Guests:         eventGuest.NewEventGuestRepository(database),
+		UserRepository: user.NewUserRepository(database),
+		Config:         &configs.Config{Auth: configs.AuthConfig{Secret: "secret"}},
+	}
+	token := rsvp.Sign(rsvp.DeriveKey("secret"), rsvp.Claims{EventID: 7, GuestID: 3, Action: rsvp.ActionAccept, Expires: time.Now().Add(time.Hour)})
+	req := httptest.NewRequest(http.MethodPost, "/guests/claim", strings.NewReader(`{"token":"`+token+`"}`))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(9)))
+	rec := httptest.NewRecorder()
+	handler.ClaimGuestInvites()(rec, req)
+	//приглашение не переносится на учетную запись с чужим email
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// recordingNotifier запоминает уведомления вместо отправки, с заданной err отказывает
+type recordingNotifier struct {
+	sent []notifier.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification notifier.Notification) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestNotifyMentionsOnlyNewlyMentionedParticipants(t *testing.T) {
+	notifications := &recordingNotifier{}
+	handler := &EventHandler{Notifier: notifications}
+	participants := []models.User{
+		{Model: gorm.Model{ID: 1}, Username: "anna"},
+		{Model: gorm.Model{ID: 2}, Username: "Boris"},
+		{Model: gorm.Model{ID: 3}, Username: "me"},
+		{Model: gorm.Model{ID: 4}, Username: "carl"},
+	}
+	before := &models.Event{Description: "Отчет готовит @anna"}
+	after := &models.Event{Description: "Отчет готовит @anna", Agenda: "Вопросы к @boris. Итоги от @me", Minutes: "@stranger"}
+
+	err := handler.notifyMentions(context.Background(), before, after, participants, 3, notifier.EventDetails{ID: 5})
+	require.NoError(t, err)
+	//anna упомянута и раньше, автор изменения и не участник уведомление не получают
+	require.Len(t, notifications.sent, 1)
+	require.Equal(t, notifier.KindMentioned, notifications.sent[0].Kind)
+	require.Equal(t, uint(2), notifications.sent[0].Recipient.UserID)
+}
+
+// expectRescheduleUntilBusyCheck ожидает запросы переноса события 5 с участником 2 вплоть до проверки его занятости
+func expectRescheduleUntilBusyCheck(mock sqlmock.Sqlmock) {
+	oldStart := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE organization_id = \$1 AND "events"."id" = \$2`).
+		WithArgs(uint(1), uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "start_date", "duration", "creator_id", "organization_id", "version"}).
+			AddRow(5, "Планирование", oldStart, 30, 1, 1, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE (id = $1 AND creator_id = $2)`)).
+		WithArgs(uint(5), uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "events" SET .* WHERE version = \$\d+`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT users.id, users.username.* FROM "event_participants" JOIN users`).
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email"}).
+			AddRow(1, "organizer", "organizer@example.com").
+			AddRow(2, "participant", "participant@example.com"))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+		WithArgs(uint(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "organizer"))
+	mock.ExpectQuery(`SELECT \* FROM "event_participants" WHERE \(event_id IN \(\$1\) AND user_id IN \(\$2,\$3\)\)`).
+		WithArgs(uint(5), uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "user_id", "role", "status"}).
+			AddRow(1, 5, 1, models.RoleOrganizer, models.StatusAccepted).
+			AddRow(2, 5, 2, models.RoleRequired, models.StatusDecline))
+	mock.ExpectQuery(`SELECT \* FROM "out_of_offices"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	//перенесенное событие исключается из проверки занятости, других встреч нет
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(CASE WHEN events.focus .* WHERE \(ep.user_id = \$\d+ AND events.id <> \$\d+\) AND ep.status NOT IN`).
+		WillReturnRows(sqlmock.NewRows([]string{"level"}).AddRow(models.BusyFree))
+}
+
+// reschedule переносит событие 5 на 15:00 от имени организатора
+func reschedule(t *testing.T, database *db.Db, notifications *recordingNotifier) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := &EventHandler{
+		EventRepository:  NewEventRepository(database),
+		UserRepository:   user.NewUserRepository(database),
+		EventParticipant: eventParticipant.NewEventParticipantRepository(database),
+		OutOfOffice:      outOfOffice.NewOutOfOfficeRepository(database),
+		Holidays:         holiday.NewHolidayRepository(database),
+		History:          eventHistory.NewEventHistoryRepository(database),
+		Guests:           eventGuest.NewEventGuestRepository(database),
+		Notifier:         notifications,
+		Config: &configs.Config{
+			Auth:   configs.AuthConfig{Secret: "secret"},
+			Public: configs.PublicConfig{BaseURL: "https://meeting.example.com"},
+		},
+	}
+	router := chi.NewRouter()
+	router.Put("/event/{id}", handler.UpdateEvent())
+	body := `{"title":"Планирование","start_date":"2025-06-02 15:00","duration":30,"creator_id":1}`
+	req := httptest.NewRequest(http.MethodPut, "/event/5", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(1))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(1))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req.WithContext(ctx))
+	return rec
+}
+
+func TestRescheduleReinvitesFreeParticipant(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	expectRescheduleUntilBusyCheck(mock)
+	//прежний отказ сбрасывается: участник снова ждет ответа, а не считается принявшим
+	mock.ExpectExec(`UPDATE "event_participants" SET "responded_at"=\$1,"status"=\$2,"status_message"=\$3`).
+		WithArgs(nil, models.StatusSent, "", sqlmock.AnyArg(), uint(5), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "event_guests" WHERE event_id = \$1`).
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "events" WHERE .* FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM "event_revisions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO "event_revisions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`FROM holidays h`).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "date", "name"}))
+	mock.ExpectCommit()
+
+	notifications := &recordingNotifier{}
+	rec := reschedule(t, database, notifications)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp EventResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, []uint{2}, resp.Reinvited)
+	require.Len(t, resp.Status, 1)
+	require.Equal(t, models.StatusSent, resp.Status[0].Status)
+	require.Equal(t, models.RoleRequired, resp.Status[0].Role)
+	require.Len(t, notifications.sent, 1)
+	require.Equal(t, notifier.KindInvited, notifications.sent[0].Kind)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRescheduleRollsBackWhenNotifyFails(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	expectRescheduleUntilBusyCheck(mock)
+	//приглашение не поставлено в очередь: перенос не сохраняется
+	mock.ExpectRollback()
+
+	rec := reschedule(t, database, &recordingNotifier{err: errors.New("outbox unavailable")})
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptRequiresParticipant(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE organization_id = \$1`).
+		WithArgs(uint(1), uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(7, "Планерка"))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "event_participants" WHERE \(event_id = \$1 AND user_id = \$2\)`).
+		WithArgs(uint(7), uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	handler := &EventHandler{
+		EventRepository:  NewEventRepository(database),
+		EventParticipant: eventParticipant.NewEventParticipantRepository(database),
+	}
+	router := chi.NewRouter()
+	router.HandleFunc("POST /event/{id}/accept/{userid}", handler.Accept())
+
+	req := httptest.NewRequest(http.MethodPost, "/event/7/accept/4", nil)
+	ctx := context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(4))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(1))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req.WithContext(ctx))
+	//не участник не получает ответа и не пишет историю
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEventRequiresOrganizer(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE organization_id = \$1`).
+		WithArgs(uint(1), uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "creator_id"}).AddRow(7, "Планерка", 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE (id = $1 AND creator_id = $2)`)).
+		WithArgs(uint(7), uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "event_participants" WHERE (event_id = $1 AND user_id = $2 AND role IN ($3,$4))`)).
+		WithArgs(uint(7), uint(4), models.RoleOrganizer, models.RoleCoOrganizer).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT \* FROM "calendar_shares"`).
+		WithArgs(uint(4), uint(4), uint(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "grantee_user_id", "level"}))
+
+	handler := &EventHandler{
+		EventRepository:  NewEventRepository(database),
+		EventParticipant: eventParticipant.NewEventParticipantRepository(database),
+		CalendarShares:   calendarShare.NewCalendarShareRepository(database),
+	}
+	router := chi.NewRouter()
+	router.HandleFunc("DELETE /event/{id}", handler.DeleteEvent())
+
+	req := httptest.NewRequest(http.MethodDelete, "/event/7", nil)
+	ctx := context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(4))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(1))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req.WithContext(ctx))
+	//обычный участник не может удалить событие, транзакция не открывается
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsUserBusyCountsOnlyActiveParticipations(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+
+	//отказы и удаленные участия не занимают время, календарь создателя проверяется только для него самого
+	mock.ExpectQuery(regexp.QuoteMeta(`JOIN event_participants ep ON ep.event_id = events.id AND ep.deleted_at IS NULL `+
+		`LEFT JOIN calendars c ON c.id = events.calendar_id AND c.owner_id = ep.user_id AND c.deleted_at IS NULL `+
+		`WHERE (ep.user_id = $4 AND events.id <> $5) AND ep.status NOT IN ($6,$7) AND (c.id IS NULL OR c.counts_toward_busy)`)).
+		WithArgs(models.BusySoft, models.BusyHard, models.BusyFree, uint(2), uint(5),
+			models.StatusDecline, models.StatusOutOfOffice, start, start.Add(30*time.Minute)).
+		WillReturnRows(sqlmock.NewRows([]string{"level"}).AddRow(models.BusyHard))
+
+	level, err := NewEventRepository(&db.Db{DB: gormDB}).IsUserBusy(2, start, 30, 5)
+	require.NoError(t, err)
+	require.Equal(t, models.BusyHard, level)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRSVPHidesInternalErrors(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+	start := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	mock.ExpectQuery(`SELECT \* FROM "users"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "locale"}).AddRow(4, "en"))
+	mock.ExpectQuery(`SELECT \* FROM "events" WHERE "events"."id" = \$1`).
+		WithArgs(uint(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "start_date", "duration"}).AddRow(7, "Планерка", start, 60))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "event_participants"`).
+		WillReturnError(errors.New(`pq: relation "event_participants" does not exist`))
+
+	handler := &EventHandler{
+		EventRepository:  NewEventRepository(database),
+		EventParticipant: eventParticipant.NewEventParticipantRepository(database),
+		UserRepository:   user.NewUserRepository(database),
+		Config:           &configs.Config{Auth: configs.AuthConfig{Secret: "secret"}},
+	}
+	router := chi.NewRouter()
+	router.HandleFunc("GET /rsvp/{token}", handler.RSVP())
+
+	accept, _ := handler.rsvpLinks(&models.Event{Model: gorm.Model{ID: 7}, StartDate: start, Duration: 60}, 4, 0)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strings.TrimPrefix(accept, handler.Config.Public.BaseURL), nil))
+	//текст ошибки базы не попадает на публичную страницу
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Contains(t, rec.Body.String(), "We could not save your response")
+	require.NotContains(t, rec.Body.String(), "event_participants")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveGuestsReturnsLookupErrors(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = \$1`).
+		WithArgs("new@example.com", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE email = \$1`).
+		WithArgs("colleague@example.com", 1).
+		WillReturnError(errors.New("connection reset"))
+
+	handler := &EventHandler{UserRepository: user.NewUserRepository(database)}
+	body := &EventRequest{InvatedUsers: []InviteUsers{{Email: "New@example.com"}, {Email: "colleague@example.com"}}}
+	//сбой поиска не делает сотрудника внешним гостем
+	_, err := handler.resolveGuests(body, 1)
+	require.EqualError(t, err, "connection reset")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSuggestSlotsIgnoresOptionalAttendees(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+	//понедельник, рабочее время организатора в UTC
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE id = \$1`).
+		WithArgs(uint(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "timezone"}).AddRow(1, "UTC"))
+	for _, id := range []uint{2, 3} {
+		mock.ExpectQuery(`SELECT \* FROM "users" WHERE \(id = \$1 AND organization_id = \$2\)`).
+			WithArgs(id, uint(4), 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	}
+	busy := map[uint][]time.Time{
+		1: nil,
+		2: {at(9, 0)},  //обязательный участник занят с 9:00 до 10:00
+		3: {at(10, 0)}, //необязательный участник занят с 10:00 до 11:00
+	}
+	for _, id := range []uint{1, 2, 3} {
+		rows := sqlmock.NewRows([]string{"id", "start_date", "duration"})
+		for _, start := range busy[id] {
+			rows.AddRow(10+id, start, 60)
+		}
+		mock.ExpectQuery(`SELECT "events"\..* FROM "events" JOIN event_participants ep`).
+			WithArgs(id, at(9, 0), at(11, 0)).
+			WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT \* FROM "out_of_offices" WHERE \(user_id = \$1 AND end_date > \$2\)`).
+			WithArgs(id, at(9, 0)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+
+	handler := &EventHandler{
+		EventRepository: NewEventRepository(database),
+		UserRepository:  user.NewUserRepository(database),
+		OutOfOffice:     outOfOffice.NewOutOfOfficeRepository(database),
+	}
+	body := `{"invated_users":[{"user_id":2},{"user_id":3,"role":"optional"}],"duration":30,"from":"2025-06-02 09:00","to":"2025-06-02 11:00"}`
+	req := httptest.NewRequest(http.MethodPost, "/event/suggest-slots", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(1))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(4))
+	rec := httptest.NewRecorder()
+	handler.SuggestSlots()(rec, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp SuggestSlotsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Slots, 2)
+	require.Equal(t, at(10, 0), resp.Slots[0].Start)
+	require.Equal(t, []uint{3}, resp.Slots[0].OptionalBusy)
+	require.Equal(t, at(10, 30), resp.Slots[1].Start)
+	require.Equal(t, []uint{3}, resp.Slots[1].OptionalBusy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,180 @@
+package event
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/xlsx"
+)
+
+// exportBatchSize сколько событий выгружается одним запросом к БД
+const exportBatchSize = 500
+
+// exportHeader заголовки колонок выгрузки
+var exportHeader = []any{"Title", "Start", "Duration (min)", "Organizer", "Participant", "Email", "Role", "Status", "Responded at"}
+
+// rowWriter записывает строки выгрузки в выбранном формате
+type rowWriter interface {
+	WriteRow(values ...any) error
+	// Flush отправляет клиенту накопленные строки
+	Flush() error
+	// Close завершает файл выгрузки
+	Close() error
+}
+
+// ExportEvents выгружает события, видимые пользователю, с участниками и их ответами в CSV или XLSX.
+// Фильтры те же, что у списка событий. Строки читаются из БД пачками и сразу пишутся в ответ.
+func (h *EventHandler) ExportEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "csv"
+		}
+		if format != "csv" && format != "xlsx" {
+			http.Error(w, "Format should be csv or xlsx", http.StatusBadRequest)
+			return
+		}
+		tagID, err := parseTagFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ownerIDs, err := h.sharedOwnerIDs(userId)
+		if err != nil {
+			http.Error(w, "Failed to fetch calendar shares", http.StatusInternalServerError)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		refs, err := h.EventRepository.FindVisibleRefs(userId, orgID, ownerIDs, tagID)
+		if err != nil {
+			http.Error(w, "Failed to fetch events", http.StatusInternalServerError)
+			return
+		}
+		access, err := h.eventAccess(refs, userId)
+		if err != nil {
+			http.Error(w, "Failed to check events access", http.StatusInternalServerError)
+			return
+		}
+		//участники и ответы выгружаются только для событий с доступом к подробностям
+		eventIDs := make([]uint, 0, len(refs))
+		for _, ref := range refs {
+			if access[ref.ID].Allows(models.ShareDetails) {
+				eventIDs = append(eventIDs, ref.ID)
+			}
+		}
+
+		var out rowWriter
+		if format == "xlsx" {
+			w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+			w.Header().Set("Content-Disposition", `attachment; filename="events.xlsx"`)
+			sheet, err := xlsx.NewWriter(w, "Events")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			out = &xlsxRows{sheet}
+		} else {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="events.csv"`)
+			out = &csvRows{csv.NewWriter(w)}
+		}
+
+		//после начала ответа статус уже не изменить: при ошибке выгрузка обрывается
+		if err := out.WriteRow(exportHeader...); err != nil {
+			return
+		}
+		flusher, _ := w.(http.Flusher)
+		for start := 0; start < len(eventIDs); start += exportBatchSize {
+			end := min(start+exportBatchSize, len(eventIDs))
+			err := h.EventRepository.StreamAttendance(eventIDs[start:end], func(row *models.AttendanceRow) error {
+				var respondedAt any
+				if row.RespondedAt != nil {
+					respondedAt = *row.RespondedAt
+				}
+				return out.WriteRow(row.Title, row.StartDate, row.Duration, row.Organizer,
+					row.Participant, row.ParticipantEmail, string(row.Role), string(row.Status), respondedAt)
+			})
+			if err != nil {
+				return
+			}
+			if err := out.Flush(); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		out.Close()
+	}
+}
+
+// escapeFormula экранирует апострофом строку, которую табличный редактор принял бы за формулу:
+// названия событий и имена участников вводят пользователи
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvRows пишет строки в CSV, время в формате xlsx.TimeLayout
+type csvRows struct {
+	writer *csv.Writer
+}
+
+func (c *csvRows) WriteRow(values ...any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = escapeFormula(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case time.Time:
+			record[i] = v.Format(xlsx.TimeLayout)
+		}
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvRows) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvRows) Close() error {
+	return c.Flush()
+}
+
+// xlsxRows пишет строки в лист XLSX, строки уходят в архив сразу при записи
+type xlsxRows struct {
+	sheet *xlsx.Writer
+}
+
+func (x *xlsxRows) WriteRow(values ...any) error {
+	for i, value := range values {
+		if v, ok := value.(string); ok {
+			values[i] = escapeFormula(v)
+		}
+	}
+	return x.sheet.WriteRow(values...)
+}
+
+func (x *xlsxRows) Flush() error {
+	return nil
+}
+
+func (x *xlsxRows) Close() error {
+	return x.sheet.Close()
+}
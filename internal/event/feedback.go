@@ -0,0 +1,172 @@
+package event
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"gorm.io/gorm"
+)
+
+// SubmitFeedback сохраняет ответ участника на опрос, отправленный после встречи
+func (h *EventHandler) SubmitFeedback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, err := request.HandelBody[FeedbackRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		err = h.Feedback.Submit(foundEvent.ID, userId, body.Rating, *body.Needed, body.Comment, time.Now().UTC())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "No survey was sent to you for this event", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Feedback saved", http.StatusOK)
+	}
+}
+
+// EventFeedback возвращает организатору сводку опроса по событию
+func (h *EventHandler) EventFeedback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		canManage, err := h.canManage(foundEvent, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !canManage {
+			http.Error(w, "Only organizers can view feedback", http.StatusForbidden)
+			return
+		}
+		ids := []uint{foundEvent.ID}
+		summaries, err := h.Feedback.Summaries(ids)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		comments, err := h.feedbackComments(ids)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := EventFeedbackResponse{Comments: comments}
+		response.EventID = foundEvent.ID
+		if len(summaries) > 0 {
+			response.FeedbackSummary = summaries[0]
+		}
+		res.JsonResponse(w, response, http.StatusOK)
+	}
+}
+
+// SeriesFeedback возвращает сводку опросов по прошедшим событиям серии, которыми управляет пользователь
+func (h *EventHandler) SeriesFeedback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		seriesId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		events, err := h.EventRepository.FindSeries(seriesId, orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(events) == 0 {
+			http.Error(w, "Series not found", http.StatusNotFound)
+			return
+		}
+		now := time.Now().UTC()
+		eventIDs := make([]uint, 0, len(events))
+		for i := range events {
+			canManage, err := h.canManage(&events[i], userId)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if canManage && ended(&events[i], now) {
+				eventIDs = append(eventIDs, events[i].ID)
+			}
+		}
+		summaries, err := h.Feedback.Summaries(eventIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		comments, err := h.feedbackComments(eventIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response := SeriesFeedbackResponse{SeriesID: seriesId, Events: summaries, Comments: comments}
+		var ratingSum, neededSum float64
+		for _, s := range summaries {
+			response.Sent += s.Sent
+			response.Responses += s.Responses
+			//средние взвешиваются числом ответов по каждому событию
+			ratingSum += s.AvgRating * float64(s.Responses)
+			neededSum += s.NeededShare * float64(s.Responses)
+		}
+		if response.Responses > 0 {
+			response.AvgRating = ratingSum / float64(response.Responses)
+			response.NeededShare = neededSum / float64(response.Responses)
+		}
+		res.JsonResponse(w, response, http.StatusOK)
+	}
+}
+
+// feedbackComments возвращает комментарии из опросов без указания авторов
+func (h *EventHandler) feedbackComments(eventIDs []uint) ([]FeedbackComment, error) {
+	surveys, err := h.Feedback.Comments(eventIDs)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]FeedbackComment, 0, len(surveys))
+	for _, s := range surveys {
+		comments = append(comments, FeedbackComment{EventID: s.EventID, Comment: s.Comment, SubmittedAt: *s.SubmittedAt})
+	}
+	return comments, nil
+}
@@ -0,0 +1,74 @@
+package event
+
+import (
+	"context"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+)
+
+// GroupInviter приглашает нового участника группы в будущие события группы
+// по тем же правилам, что и при создании события
+type GroupInviter struct {
+	handler *EventHandler
+}
+
+// NewGroupInviter создает приглашающего с зависимостями обработчика событий
+func NewGroupInviter(deps EventHandlerDeps) *GroupInviter {
+	return &GroupInviter{handler: newEventHandler(deps)}
+}
+
+// InviteGroupMember добавляет пользователя в событие группы внутри транзакции tx.
+// Статус учитывает отсутствие и занятость, версия события растет, приглашение
+// уходит через outbox той же транзакции. Если пользователь уже участвует в событии,
+// возвращается пустой статус.
+func (g *GroupInviter) InviteGroupMember(ctx context.Context, tx *db.Db, link models.EventGroup, userID uint) (models.EventStatus, error) {
+	h := g.handler.withTx(tx)
+	isParticipant, err := h.EventParticipant.IsParticipant(link.EventID, userID)
+	if err != nil {
+		return "", err
+	}
+	if isParticipant {
+		return "", nil
+	}
+	ev, err := h.EventRepository.FindById(link.EventID)
+	if err != nil {
+		return "", err
+	}
+	member, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		return "", err
+	}
+	status, message, err := h.inviteStatus(userID, ev.StartDate, ev.Duration, ev.ID)
+	if err != nil {
+		return "", err
+	}
+	participant := models.NewEventParticipant(ev.ID, userID)
+	participant.Role = link.Role
+	participant.Status = status
+	participant.StatusMessage = message
+	participant.GroupID = &link.GroupID
+	if err := h.EventParticipant.Add(participant); err != nil {
+		return "", err
+	}
+	if err := h.EventRepository.BumpVersion(ev.ID); err != nil {
+		return "", err
+	}
+	err = h.History.RecordChange(ctx, ev.ID, models.RevisionParticipantAdded, models.FieldChanges{
+		models.ParticipantField(userID, "role"):     {To: link.Role},
+		models.ParticipantField(userID, "status"):   {To: status},
+		models.ParticipantField(userID, "group_id"): {To: link.GroupID},
+	})
+	if err != nil {
+		return "", err
+	}
+	if status == models.StatusAccepted {
+		details, _ := h.eventNotice(ev)
+		acceptLink, declineLink := h.rsvpLinks(ev, userID, 0)
+		if err := h.Notifier.Notify(ctx, notifier.Invited(notifier.UserRecipient(member), details, acceptLink, declineLink)); err != nil {
+			return "", err
+		}
+	}
+	return status, nil
+}
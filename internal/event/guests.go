@@ -0,0 +1,183 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventGuest"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/ics"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/rsvp"
+	"gorm.io/gorm"
+)
+
+// resolveGuests разбирает приглашенных по email: пользователи организации приглашаются как обычно,
+// остальные возвращаются как внешние гости без учетной записи. Ошибка поиска пользователя
+// не превращает его в гостя и возвращается вызывающему.
+func (h *EventHandler) resolveGuests(body *EventRequest, orgID uint) ([]InviteUsers, error) {
+	var guests []InviteUsers
+	users := make([]InviteUsers, 0, len(body.InvatedUsers))
+	seen := map[string]bool{}
+	for _, invUser := range body.InvatedUsers {
+		if invUser.UserId != 0 || invUser.Email == "" {
+			users = append(users, invUser)
+			continue
+		}
+		email := eventGuest.NormalizeEmail(invUser.Email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		foundUser, err := h.UserRepository.FindByEmail(email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if foundUser != nil && foundUser.OrganizationID == orgID {
+			invUser.UserId = foundUser.ID
+			users = append(users, invUser)
+			continue
+		}
+		invUser.Email = email
+		guests = append(guests, invUser)
+	}
+	body.InvatedUsers = users
+	return guests, nil
+}
+
+// inviteGuests сохраняет внешних гостей события и отправляет им приглашения
+func (h *EventHandler) inviteGuests(ctx context.Context, ev *models.Event, guests []InviteUsers) ([]models.EventGuest, error) {
+	invited := make([]models.EventGuest, 0, len(guests))
+	for _, g := range guests {
+		guest, err := h.Guests.Create(models.NewEventGuest(ev.ID, g.Email, g.UserName, g.Role))
+		if err != nil {
+			return nil, err
+		}
+		invited = append(invited, *guest)
+	}
+	if err := h.sendGuestInvites(ctx, ev, invited); err != nil {
+		return nil, err
+	}
+	return invited, nil
+}
+
+// sendGuestInvites отправляет гостям приглашения с подписанными ссылками для ответа и файлом .ics
+func (h *EventHandler) sendGuestInvites(ctx context.Context, ev *models.Event, guests []models.EventGuest) error {
+	if len(guests) == 0 {
+		return nil
+	}
+	organizer, err := h.UserRepository.FindByid(ev.CreatorID)
+	if err != nil {
+		return err
+	}
+	details := notifier.NewEventDetails(ev, organizer)
+	now := time.Now().UTC()
+	for _, guest := range guests {
+		acceptLink, declineLink := h.rsvpLinks(ev, 0, guest.ID)
+		invite := ics.Build(ics.Invite{
+			UID:         fmt.Sprintf("event-%d@metiing-pro", ev.ID),
+			Sequence:    ev.Version,
+			Title:       ev.Title,
+			Description: ev.Description,
+			Start:       ev.StartDate,
+			Duration:    time.Duration(ev.Duration) * time.Minute,
+			URL:         ev.ConferenceLink,
+			Organizer:   organizer.Email,
+			Attendee:    guest.Email,
+		}, now)
+		if err := h.Notifier.Notify(ctx, notifier.GuestInvited(guestRecipient(guest, organizer), details, acceptLink, declineLink, invite)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClaimGuestInvites переносит приглашения, полученные гостем, в участие пользователя.
+// Владение адресом подтверждает подписанная ссылка из письма гостю: адрес гостя должен совпадать
+// с email учетной записи. Переносятся только приглашения на события организации пользователя.
+func (h *EventHandler) ClaimGuestInvites() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[ClaimGuestsRequest](w, r)
+		if err != nil {
+			return
+		}
+		claims, err := rsvp.Verify(h.rsvpKey(), body.Token, time.Now())
+		if err != nil || claims.GuestID == 0 {
+			http.Error(w, "Invalid invitation link", http.StatusForbidden)
+			return
+		}
+		guest, err := h.Guests.FindById(claims.GuestID)
+		if err != nil || guest.EventID != claims.EventID {
+			http.Error(w, "Invitation not found", http.StatusNotFound)
+			return
+		}
+		currentUser, err := h.UserRepository.FindByid(userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if eventGuest.NormalizeEmail(currentUser.Email) != guest.Email {
+			http.Error(w, "Invitation was sent to another email", http.StatusForbidden)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		eventIDs, err := h.Guests.ConvertToUser(guest.Email, userId, orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if eventIDs == nil {
+			eventIDs = []uint{}
+		}
+		res.JsonResponse(w, ClaimGuestsResponse{EventIDs: eventIDs}, http.StatusOK)
+	}
+}
+
+// GetGuests возвращает внешних гостей события и их ответы
+func (h *EventHandler) GetGuests() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		access, err := h.eventAccess([]models.Event{*foundEvent}, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !access[foundEvent.ID].Allows(models.ShareDetails) {
+			http.Error(w, "Event not available", http.StatusForbidden)
+			return
+		}
+		guests, err := h.Guests.FindByEvent(foundEvent.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, guests, http.StatusOK)
+	}
+}
@@ -1,25 +1,37 @@
 package event
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendar"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendarShare"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventGuest"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventParticipant"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/feedback"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/outOfOffice"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/tag"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/conferencing"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/event"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/holidays"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
-	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/sendmail"
 	"github.com/go-chi/chi/v5"
-)
-
-const (
-	link string = "http://localhost:8080/event/"
+	"gorm.io/gorm"
 )
 
 type EventHandler struct {
@@ -28,6 +40,20 @@ type EventHandler struct {
 	EventParticipant *eventParticipant.EventParticipantRepository
 	JWTService       *jwt.JWT
 	Config           *configs.Config
+	Conferencing     conferencing.Provider
+	Delegations      *delegation.DelegationRepository
+	CalendarShares   *calendarShare.CalendarShareRepository
+	Calendars        *calendar.CalendarRepository
+	Groups           *group.GroupRepository
+	OutOfOffice      *outOfOffice.OutOfOfficeRepository
+	Holidays         *holiday.HolidayRepository
+	History          *eventHistory.EventHistoryRepository
+	Tags             *tag.TagRepository
+	Feedback         *feedback.FeedbackRepository
+	Guests           *eventGuest.EventGuestRepository
+	// Notifier уведомления ставятся в очередь той же транзакцией, что и изменение события:
+	// ошибка постановки отменяет сохранение
+	Notifier notifier.Notifier
 }
 
 type EventHandlerDeps struct {
@@ -36,24 +62,70 @@ type EventHandlerDeps struct {
 	EventParticipant *eventParticipant.EventParticipantRepository
 	JWTService       *jwt.JWT
 	Config           *configs.Config
+	Conferencing     conferencing.Provider
+	Delegations      *delegation.DelegationRepository
+	CalendarShares   *calendarShare.CalendarShareRepository
+	Calendars        *calendar.CalendarRepository
+	Groups           *group.GroupRepository
+	OutOfOffice      *outOfOffice.OutOfOfficeRepository
+	Holidays         *holiday.HolidayRepository
+	History          *eventHistory.EventHistoryRepository
+	Tags             *tag.TagRepository
+	Feedback         *feedback.FeedbackRepository
+	Guests           *eventGuest.EventGuestRepository
+	Notifier         notifier.Notifier
 }
 
 func NewEventHandler(mux *chi.Mux, deps EventHandlerDeps) {
-	handler := &EventHandler{
+	handler := newEventHandler(deps)
+	mux.Handle("POST /event/", middleware.IsAuthedAs(handler.CreateEvent(), handler.JWTService, handler.Delegations))
+	mux.HandleFunc("GET /rsvp/{token}", handler.RSVP())
+	mux.Handle("GET /event/{id}", middleware.IsAuthedAs(handler.GetEventById(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /event/{id}", middleware.IsAuthedAs(handler.UpdateEvent(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /event/{id}", middleware.IsAuthedAs(handler.DeleteEvent(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/with-creators", middleware.IsAuthedAs(handler.GetEventsWithCreators(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/{id}/with-creator", middleware.IsAuthedAs(handler.GetEventWithCreator(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /event/{id}/accept/{userid}", middleware.IsAuthedAs(handler.Accept(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /event/{id}/decline/{userid}", middleware.IsAuthedAs(handler.Decline(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/{id}/guests", middleware.IsAuthedAs(handler.GetGuests(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /guests/claim", middleware.IsAuthed(handler.ClaimGuestInvites(), handler.JWTService))
+	mux.Handle("PUT /event/{id}/tags", middleware.IsAuthedAs(handler.SetTags(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /event/{id}/check-in", middleware.IsAuthedAs(handler.CheckIn(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /event/{id}/attendance/{user_id}", middleware.IsAuthedAs(handler.MarkAttendance(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/{id}/attendance", middleware.IsAuthedAs(handler.EventAttendance(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/series/{id}/attendance", middleware.IsAuthedAs(handler.SeriesAttendance(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /event/{id}/feedback", middleware.IsAuthed(handler.SubmitFeedback(), handler.JWTService))
+	mux.Handle("GET /event/{id}/feedback", middleware.IsAuthedAs(handler.EventFeedback(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/series/{id}/feedback", middleware.IsAuthedAs(handler.SeriesFeedback(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /user/{id}/attendance", middleware.IsAuthedAs(handler.UserAttendance(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /events/export", middleware.IsAuthedAs(handler.ExportEvents(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /events/search", middleware.IsAuthedAs(handler.SearchEvents(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /event/{id}/history", middleware.IsAuthedAs(handler.GetHistory(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /event/suggest-slots", middleware.IsAuthedAs(handler.SuggestSlots(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /calendar/{user_id}/freebusy", middleware.IsAuthedAs(handler.FreeBusy(), handler.JWTService, handler.Delegations))
+}
+
+// newEventHandler собирает обработчик событий из зависимостей
+func newEventHandler(deps EventHandlerDeps) *EventHandler {
+	return &EventHandler{
 		EventRepository:  deps.EventRepository,
 		UserRepository:   deps.UserRepository,
 		EventParticipant: deps.EventParticipant,
 		JWTService:       deps.JWTService,
 		Config:           deps.Config,
+		Conferencing:     deps.Conferencing,
+		Delegations:      deps.Delegations,
+		CalendarShares:   deps.CalendarShares,
+		Calendars:        deps.Calendars,
+		Groups:           deps.Groups,
+		OutOfOffice:      deps.OutOfOffice,
+		Holidays:         deps.Holidays,
+		History:          deps.History,
+		Tags:             deps.Tags,
+		Feedback:         deps.Feedback,
+		Guests:           deps.Guests,
+		Notifier:         deps.Notifier,
 	}
-	mux.Handle("POST /event/", middleware.IsAuthed(handler.CreateEvent(), handler.JWTService))
-	mux.Handle("GET /event/{id}", middleware.IsAuthed(handler.GetEventById(), handler.JWTService))
-	mux.Handle("PUT /event/{id}", middleware.IsAuthed(handler.UpdateEvent(), handler.JWTService))
-	mux.Handle("DELETE /event/{id}", middleware.IsAuthed(handler.DeleteEvent(), handler.JWTService))
-	mux.Handle("GET /event/with-creators", middleware.IsAuthed(handler.GetEventsWithCreators(), handler.JWTService))
-	mux.Handle("GET /event/{id}/with-creator", middleware.IsAuthed(handler.GetEventWithCreator(), handler.JWTService))
-	mux.Handle("PUT /event/{id}/accept/{userid}", middleware.IsAuthed(handler.Accept(), handler.JWTService))
-	mux.Handle("PUT /event/{id}/decline/{userid}", middleware.IsAuthed(handler.Decline(), handler.JWTService))
 }
 
 // GetEventById Получает событие по его ID
@@ -72,11 +144,36 @@ func (h *EventHandler) GetEventById() http.HandlerFunc {
 		}
 		id := uint(idUint)
 
-		events, err := h.EventRepository.FindById(id)
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		orgID, _ := middleware.OrganizationID(r.Context())
+		events, err := h.EventRepository.FindInOrganization(id, orgID)
 		if err != nil {
 			http.Error(w, "Failed to fetch events", http.StatusInternalServerError)
 			return
 		}
+		//проверяем доступ к событию
+		access, err := h.eventAccess([]models.Event{*events}, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		switch access[events.ID] {
+		case "":
+			http.Error(w, "Event not available", http.StatusForbidden)
+			return
+		}
+		//версия нужна клиенту для If-Match при изменении и удалении
+		w.Header().Set("ETag", request.ETag(events.Version))
+		switch access[events.ID] {
+		case models.ShareFreeBusy:
+			res.JsonResponse(w, events.FreeBusyView(), http.StatusOK)
+			return
+		}
 		res.JsonResponse(w, events, http.StatusOK)
 	}
 }
@@ -90,70 +187,251 @@ func (h *EventHandler) CreateEvent() http.HandlerFunc {
 			http.Error(w, "Неверный запрос", http.StatusBadRequest)
 			return
 		}
+		//событие можно создать только от своего имени или от имени владельца при делегировании
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if body.CreatorID != userId {
+			http.Error(w, "creator_id must match the authorized user or the delegator", http.StatusForbidden)
+			return
+		}
+		//приглашать можно только пользователей своей организации
+		orgID, _ := middleware.OrganizationID(r.Context())
 		//валидируем время из запроса
 		startTime, err := request.ValidateTime(body.StartDate)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		//проверяем роли приглашенных
+		for i := range body.InvatedUsers {
+			if body.InvatedUsers[i].Role == "" {
+				body.InvatedUsers[i].Role = models.RoleRequired
+			}
+			if !body.InvatedUsers[i].Role.IsValidInviteRole() {
+				http.Error(w, "Invalid participant role", http.StatusBadRequest)
+				return
+			}
+		}
+		//приглашенные по email без учетной записи в организации становятся гостями
+		guests, err := h.resolveGuests(body, orgID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//раскрываем приглашенные группы в отдельных участников
+		invitees, ok := h.expandGroups(w, body)
+		if !ok {
+			return
+		}
+		//событие можно добавить только в свой календарь
+		if ok := h.checkCalendar(w, body.CalendarID, body.CreatorID); !ok {
+			return
+		}
+		//метки должны быть доступны создателю
+		if ok := h.checkTags(w, body.TagIDs, body.CreatorID, orgID); !ok {
+			return
+		}
+		//новое событие серии можно добавить только к событию, которым пользователь управляет
+		var seriesRoot *models.Event
+		if body.SeriesID != nil {
+			seriesRoot, err = h.EventRepository.FindInOrganization(*body.SeriesID, orgID)
+			if err != nil {
+				http.Error(w, "Series event not found", http.StatusBadRequest)
+				return
+			}
+			canManage, err := h.canManage(seriesRoot, userId)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !canManage {
+				http.Error(w, "Only organizers can extend the series", http.StatusForbidden)
+				return
+			}
+		}
 		//создаем новое событие
 		newEvent := models.NewEvent(body.Title, body.Description, body.Duration, body.CreatorID, startTime)
+		newEvent.CalendarID = body.CalendarID
+		newEvent.OrganizationID = orgID
+		newEvent.Agenda = body.Agenda
+		newEvent.Minutes = body.Minutes
+		if seriesRoot != nil {
+			seriesID := seriesRoot.ID
+			if seriesRoot.SeriesID != nil {
+				seriesID = *seriesRoot.SeriesID
+			}
+			newEvent.SeriesID = &seriesID
+		}
+		//если нужна видеовстреча, генерируем ссылку
+		if body.Conferencing {
+			conferenceLink, err := h.Conferencing.CreateMeeting(body.Title)
+			if err != nil {
+				http.Error(w, "Not possible to create conference link", http.StatusInternalServerError)
+				return
+			}
+			newEvent.ConferenceLink = conferenceLink
+		}
+		//событие, участники, история и письма сохраняются одной транзакцией,
+		//дальше h работает в ней
+		h, tx, err := h.begin()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
 		//создаем событие в БД
 		createdEvent, err := h.EventRepository.Create(newEvent)
 		if err != nil {
 			http.Error(w, "Not possible to create new event", http.StatusInternalServerError)
 			return
 		}
+		//создатель события становится его организатором
+		err = h.EventParticipant.AddParticipantWithRole(createdEvent.ID, createdEvent.CreatorID, models.RoleOrganizer, models.StatusAccepted)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		//первое событие серии становится ее идентификатором
+		if seriesRoot != nil && seriesRoot.SeriesID == nil {
+			if err := h.EventRepository.SetSeries(seriesRoot.ID, seriesRoot.ID); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if len(body.TagIDs) > 0 {
+			if err := h.Tags.SetEventTags(createdEvent.ID, body.TagIDs); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
 		//логика проверки занятости пользователя
 		var userStatusInvate []models.UserStatus
-		for _, invUser := range body.InvatedUsers {
+		var optionalStatus []models.UserStatus
+		var details notifier.EventDetails
+		if len(invitees) > 0 {
+			details, _ = h.eventNotice(createdEvent)
+		}
+		var invitedUsers []models.User
+		for _, invUser := range invitees {
 			//ищем имя пользователя для ответа по юзер ИД из запроса
-			foundUser, err := h.UserRepository.FindByid(invUser.UserId)
+			foundUser, err := h.UserRepository.FindInOrganization(invUser.UserId, orgID)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			//поиск занятости пользователя
-			isBusy := h.EventRepository.IsUserBusy(invUser.UserId, startTime, body.Duration)
-			//если нашли пересечения
-			status := models.StatusBusy
-			//если нет пересечений то статус принято и отправляем уведомление на емейл или в лк
-			if !isBusy {
-
-				//подготавливаем ссылки
-				strEventId := strconv.FormatUint(uint64(createdEvent.ID), 10)
-				strUserId := strconv.FormatUint(uint64(invUser.UserId), 10)
-
-				acceptLink := link + strEventId + "/" + "accept" + "/" + strUserId
-				declineLink := link + strEventId + "/" + "decline" + "/" + strUserId
-				sendmail.SendMail(h.Config, acceptLink, declineLink)
+			invitedUsers = append(invitedUsers, *foundUser)
+			//поиск отсутствия и занятости пользователя
+			status, message, err := h.inviteStatus(invUser.UserId, startTime, body.Duration, createdEvent.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			//если пользователь свободен, отправляем уведомление на емейл или в лк
+			if status == models.StatusAccepted {
+				//подписанные ссылки для ответа одним кликом, без авторизации
+				acceptLink, declineLink := h.rsvpLinks(createdEvent, invUser.UserId, 0)
+				if err := h.Notifier.Notify(r.Context(), notifier.Invited(notifier.UserRecipient(foundUser), details, acceptLink, declineLink)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
 
 			}
 			user := models.UserStatus{
 				UserId:   invUser.UserId,
 				UserName: foundUser.Username,
 				Status:   status,
+				Role:     invUser.Role,
+				GroupID:  invUser.GroupID,
+				Message:  message,
+			}
+			//занятость необязательных участников показываем отдельно
+			if invUser.Role == models.RoleOptional {
+				optionalStatus = append(optionalStatus, user)
+			} else {
+				userStatusInvate = append(userStatusInvate, user)
 			}
-			userStatusInvate = append(userStatusInvate, user)
 		}
 
 		//добавляем участников
-		for _, user := range userStatusInvate {
-			err := h.EventParticipant.AddParticipant(createdEvent.ID, user.UserId)
-			if err != nil {
+		for _, user := range append(userStatusInvate, optionalStatus...) {
+			participant := models.NewEventParticipant(createdEvent.ID, user.UserId)
+			participant.Role = user.Role
+			participant.Status = user.Status
+			participant.StatusMessage = user.Message
+			participant.GroupID = user.GroupID
+			if err := h.EventParticipant.Add(participant); err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
 		}
+		//приглашение остается связанным с группой
+		for _, invGroup := range body.InvitedGroups {
+			err := h.Groups.LinkEvent(createdEvent.ID, invGroup.GroupID, invGroup.Role, invGroup.IncludeNewMembers)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		//гости получают письмо со ссылкой для ответа без учетной записи
+		invitedGuests, err := h.inviteGuests(r.Context(), createdEvent, guests)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//участники, упомянутые в описании, повестке или протоколе, получают отдельное уведомление
+		if err := h.notifyMentions(r.Context(), nil, createdEvent, invitedUsers, userId, details); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//первая версия истории содержит все поля события и список участников
+		changes := models.EventSnapshot(createdEvent)
+		participantIDs := []uint{createdEvent.CreatorID}
+		for _, invUser := range invitees {
+			participantIDs = append(participantIDs, invUser.UserId)
+		}
+		changes["participants"] = models.FieldChange{To: participantIDs}
+		if len(guests) > 0 {
+			guestEmails := make([]string, 0, len(guests))
+			for _, guest := range guests {
+				guestEmails = append(guestEmails, guest.Email)
+			}
+			changes["guests"] = models.FieldChange{To: guestEmails}
+		}
+		if err := h.History.RecordChange(r.Context(), createdEvent.ID, models.RevisionCreated, changes); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		//предупреждаем, если событие попало на праздник
+		userIDs := []uint{createdEvent.CreatorID}
+		for _, invUser := range invitees {
+			userIDs = append(userIDs, invUser.UserId)
+		}
+		warnings, err := h.holidayWarnings(userIDs, startTime, body.Duration)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		//Собираем ответ
 
 		respEvent := &EventResponse{
-			Title:       createdEvent.Title,
-			Description: createdEvent.Description,
-			StartDate:   body.StartDate,
-			Duration:    createdEvent.Duration,
-			Status:      userStatusInvate,
+			Title:          createdEvent.Title,
+			Description:    createdEvent.Description,
+			StartDate:      body.StartDate,
+			Duration:       createdEvent.Duration,
+			ConferenceLink: createdEvent.ConferenceLink,
+			Status:         userStatusInvate,
+			OptionalStatus: optionalStatus,
+			Guests:         invitedGuests,
+			Warnings:       warnings,
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		res.JsonResponse(w, respEvent, http.StatusCreated)
@@ -175,11 +453,17 @@ func (h *EventHandler) UpdateEvent() http.HandlerFunc {
 			return
 		}
 
-		hasEvent, err := h.EventRepository.FindById(eventId)
+		orgID, _ := middleware.OrganizationID(r.Context())
+		hasEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
 		if err != nil {
 			http.Error(w, "Event not found", http.StatusBadRequest)
 			return
 		}
+		//клиент редактировал устаревшую версию события
+		if !request.IfMatch(r, request.ETag(hasEvent.Version)) {
+			http.Error(w, "Event was modified", http.StatusPreconditionFailed)
+			return
+		}
 		//обрабатыввем запрос
 		body, err := request.HandelBody[EventRequest](w, r)
 		if err != nil {
@@ -191,66 +475,214 @@ func (h *EventHandler) UpdateEvent() http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		//проверяем является ли юзер создателем события
-		if hasEvent.CreatorID != userId {
-			http.Error(w, "You are not creator,only creator can update event", http.StatusBadRequest)
+		//проверяем является ли юзер организатором события
+		canManage, err := h.canManage(hasEvent, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if !canManage {
+			http.Error(w, "You are not organizer,only organizers can update event", http.StatusBadRequest)
+			return
+		}
+
+		//запоминаем событие до изменений для истории
+		before := *hasEvent
+
+		//при переносе встречи старая ссылка на видеовстречу больше не действует
+		rescheduled := !hasEvent.StartDate.Equal(startTime) || hasEvent.Duration != body.Duration
+		conferenceLink, err := h.refreshConferenceLink(hasEvent, body.Title, body.Conferencing, rescheduled)
+		if err != nil {
+			http.Error(w, "Not possible to update conference link", http.StatusInternalServerError)
+			return
+		}
+
+		//переносим событие в другой календарь создателя
+		if body.CalendarID != nil {
+			if ok := h.checkCalendar(w, body.CalendarID, hasEvent.CreatorID); !ok {
+				return
+			}
+			hasEvent.CalendarID = body.CalendarID
+		}
 
 		//Заполняем событие новыми данными
 		hasEvent.Title = body.Title
 		hasEvent.Description = body.Description
+		hasEvent.Agenda = body.Agenda
+		hasEvent.Minutes = body.Minutes
 		hasEvent.StartDate = startTime
 		hasEvent.Duration = body.Duration
+		hasEvent.ConferenceLink = conferenceLink
 
-		updatedEvent, err := h.EventRepository.Update(hasEvent)
+		//изменение, новые статусы участников, история и письма сохраняются одной транзакцией,
+		//дальше h работает в ней
+		h, tx, err := h.begin()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+		//сохраняем только если событие не изменили после чтения
+		updatedEvent, err := h.EventRepository.UpdateIfVersion(hasEvent, before.Version)
+		if errors.Is(err, ErrVersionConflict) {
+			http.Error(w, "Event was modified", http.StatusPreconditionFailed)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		w.Header().Set("ETag", request.ETag(updatedEvent.Version))
 		//получаем участников события
 		partUserEvent, err := h.EventParticipant.GetEventParticipants(updatedEvent.ID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		details, organizer := h.eventNotice(updatedEvent)
+		changes := models.DiffEvents(&before, updatedEvent)
+		participantIDs := make([]uint, 0, len(partUserEvent))
+		for _, invUser := range partUserEvent {
+			participantIDs = append(participantIDs, invUser.ID)
+		}
+		participations, err := h.EventParticipant.FindParticipations([]uint{updatedEvent.ID}, participantIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		previous := make(map[uint]models.EventParticipant, len(participations))
+		for _, p := range participations {
+			previous[p.UserID] = p
+		}
 		var userStatusInvate []models.UserStatus
+		reinvited := []uint{}
+		userIDs := []uint{updatedEvent.CreatorID}
 		for _, invUser := range partUserEvent {
-
-			//поиск занятости пользователя
-			isBusy := h.EventRepository.IsUserBusy(invUser.ID, startTime, body.Duration)
-			//если нашли пересечения
-			status := models.StatusBusy
-			//если нет пересечений то статус принято
-			if !isBusy {
-
-				//подготавливаем ссылки
-				strEventId := strconv.FormatUint(uint64(updatedEvent.ID), 10)
-				strUserId := strconv.FormatUint(uint64(invUser.ID), 10)
-
-				acceptLink := link + strEventId + "/" + "accept" + "/" + strUserId
-				declineLink := link + strEventId + "/" + "decline" + "/" + strUserId
-				sendmail.SendMail(h.Config, acceptLink, declineLink)
+			//организатор не получает повторное приглашение на свое событие
+			if invUser.ID == updatedEvent.CreatorID {
+				continue
 			}
+			userIDs = append(userIDs, invUser.ID)
+			participation := previous[invUser.ID]
 			user := models.UserStatus{
 				UserId:   invUser.ID,
 				UserName: invUser.Username,
-				Status:   status,
+				Status:   participation.Status,
+				Role:     participation.Role,
+				GroupID:  participation.GroupID,
+				Message:  participation.StatusMessage,
 			}
+
+			//время не изменилось: ответы участников сохраняются
+			if !rescheduled {
+				if body.NotifyParticipants {
+					if err := h.Notifier.Notify(r.Context(), notifier.Updated(notifier.UserRecipient(&invUser), details)); err != nil {
+						http.Error(w, err.Error(), http.StatusInternalServerError)
+						return
+					}
+				}
+				userStatusInvate = append(userStatusInvate, user)
+				continue
+			}
+			//соорганизаторы ведут событие вместе с организатором: их ответ не сбрасывается,
+			//но о новом времени они узнают
+			if participation.Role.CanManage() {
+				if err := h.Notifier.Notify(r.Context(), notifier.Updated(notifier.UserRecipient(&invUser), details)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				userStatusInvate = append(userStatusInvate, user)
+				continue
+			}
+
+			//событие перенесено: ответ сбрасывается, заново ищем отсутствие и занятость пользователя,
+			//само событие при этом не считается пересечением
+			status, message, err := h.inviteStatus(invUser.ID, startTime, body.Duration, updatedEvent.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if status == models.StatusAccepted {
+				//свободный участник отвечает заново: прежний ответ, в том числе отказ, относился к старому времени
+				status = models.StatusSent
+				//он получает повторное приглашение с подписанными ссылками для ответа
+				acceptLink, declineLink := h.rsvpLinks(updatedEvent, invUser.ID, 0)
+				if err := h.Notifier.Notify(r.Context(), notifier.Invited(notifier.UserRecipient(&invUser), details, acceptLink, declineLink)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			} else {
+				//занятый или отсутствующий участник все равно узнает о новом времени
+				if err := h.Notifier.Notify(r.Context(), notifier.Updated(notifier.UserRecipient(&invUser), details)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+			user.Status = status
+			user.Message = message
 			userStatusInvate = append(userStatusInvate, user)
+			reinvited = append(reinvited, user.UserId)
+			if participation.Status != status {
+				changes[models.ParticipantField(user.UserId, "status")] = models.FieldChange{From: participation.Status, To: status}
+			}
 			//обновляем статусы с участнкиами событий
-			h.EventParticipant.UpdateParticipant(&models.EventParticipant{
-				EventID: eventId, //берем номер события из пути
-				UserID:  user.UserId,
-				Status:  status,
-			})
+			if err := h.EventParticipant.UpdateStatus(eventId, user.UserId, status, message); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		//впервые упомянутые в тексте события участники получают отдельное уведомление
+		if err := h.notifyMentions(r.Context(), &before, updatedEvent, partUserEvent, userId, details); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//гости получают обновленное приглашение при переносе и уведомление об изменении описания по запросу
+		guests, err := h.Guests.FindByEvent(updatedEvent.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if rescheduled && len(guests) > 0 {
+			if err := h.Guests.ResetResponses(updatedEvent.ID); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if err := h.sendGuestInvites(r.Context(), updatedEvent, guests); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		} else if body.NotifyParticipants {
+			for _, guest := range guests {
+				if err := h.Notifier.Notify(r.Context(), notifier.Updated(guestRecipient(guest, organizer), details)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+		if len(changes) > 0 {
+			if err := h.History.RecordChange(r.Context(), updatedEvent.ID, models.RevisionUpdated, changes); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		warnings, err := h.holidayWarnings(userIDs, startTime, body.Duration)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 		respEvent := &EventResponse{
-			Title:       hasEvent.Title,
-			Description: hasEvent.Description,
-			StartDate:   body.StartDate,
-			Duration:    hasEvent.Duration,
-			Status:      userStatusInvate,
+			Title:          hasEvent.Title,
+			Description:    hasEvent.Description,
+			StartDate:      body.StartDate,
+			Duration:       hasEvent.Duration,
+			ConferenceLink: updatedEvent.ConferenceLink,
+			Status:         userStatusInvate,
+			Warnings:       warnings,
+			Reinvited:      reinvited,
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		res.JsonResponse(w, respEvent, http.StatusOK)
@@ -273,16 +705,92 @@ func (h *EventHandler) DeleteEvent() http.HandlerFunc {
 		}
 		id := uint(idUint)
 
-		_, err = h.EventRepository.FindById(id)
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(id, orgID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = h.EventRepository.DeleteById(id)
+		//удалить событие могут только организатор и соорганизаторы
+		canManage, err := h.canManage(foundEvent, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !canManage {
+			http.Error(w, "Only organizers can delete event", http.StatusForbidden)
+			return
+		}
+		if !request.IfMatch(r, request.ETag(foundEvent.Version)) {
+			http.Error(w, "Event was modified", http.StatusPreconditionFailed)
+			return
+		}
+		//удаление, история и письма об отмене сохраняются одной транзакцией, дальше h работает в ней
+		h, tx, err := h.begin()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+		//получателей отмены запоминаем до удаления события
+		participants, err := h.EventParticipant.GetEventParticipants(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		guests, err := h.Guests.FindByEvent(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//удаляем только ту версию события, которую прочитали
+		err = h.EventRepository.DeleteIfVersion(id, foundEvent.Version)
+		if errors.Is(err, ErrVersionConflict) {
+			http.Error(w, "Event was modified", http.StatusPreconditionFailed)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		//встреча отменена, ссылка на видеовстречу больше не нужна
+		if foundEvent.ConferenceLink != "" {
+			if err := h.Conferencing.ReleaseMeeting(foundEvent.ConferenceLink); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if err := h.History.RecordChange(r.Context(), id, models.RevisionDeleted, models.FieldChanges{}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(participants) > 0 || len(guests) > 0 {
+			details, organizer := h.eventNotice(foundEvent)
+			for _, participant := range participants {
+				if participant.ID == foundEvent.CreatorID {
+					continue
+				}
+				if err := h.Notifier.Notify(r.Context(), notifier.Cancelled(notifier.UserRecipient(&participant), details)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+			for _, guest := range guests {
+				if err := h.Notifier.Notify(r.Context(), notifier.Cancelled(guestRecipient(guest, organizer), details)); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		resDel := &DeleteResponse{
 			Delete: true,
 		}
@@ -293,17 +801,50 @@ func (h *EventHandler) DeleteEvent() http.HandlerFunc {
 // GetEventsWithCreators Получает события вместе с их создателями
 func (h *EventHandler) GetEventsWithCreators() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		eventsWithCreators, err := h.EventRepository.GetEventsWithCreators()
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		//показываем только свои события и события из открытых календарей
+		ownerIDs, err := h.sharedOwnerIDs(userId)
+		if err != nil {
+			http.Error(w, "Failed to fetch calendar shares", http.StatusInternalServerError)
+			return
+		}
+		tagID, err := parseTagFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		eventsWithCreators, err := h.EventRepository.FindVisibleWithCreators(userId, orgID, ownerIDs, tagID)
+		if err != nil {
+			http.Error(w, "Failed to fetch events with creators", http.StatusInternalServerError)
+			return
+		}
 		if eventsWithCreators == nil {
 			res.JsonResponse(w, "Not found events", http.StatusOK)
 			return
 		}
+		access, err := h.eventAccess(eventsWithCreators, userId)
 		if err != nil {
-			http.Error(w, "Failed to fetch events with creators", http.StatusInternalServerError)
+			http.Error(w, "Failed to check events access", http.StatusInternalServerError)
 			return
 		}
+		visibleEvents := make([]*models.Event, 0, len(eventsWithCreators))
+		for i := range eventsWithCreators {
+			switch access[eventsWithCreators[i].ID] {
+			case "":
+				continue
+			case models.ShareFreeBusy:
+				visibleEvents = append(visibleEvents, eventsWithCreators[i].FreeBusyView())
+			default:
+				visibleEvents = append(visibleEvents, &eventsWithCreators[i])
+			}
+		}
 
-		res.JsonResponse(w, eventsWithCreators, http.StatusOK)
+		res.JsonResponse(w, visibleEvents, http.StatusOK)
 	}
 }
 
@@ -322,7 +863,12 @@ func (h *EventHandler) GetEventWithCreator() http.HandlerFunc {
 			return
 		}
 
-		eventWithCreator, err := h.EventRepository.GetEventWithCreator(eventID, userID)
+		orgID, _ := middleware.OrganizationID(r.Context())
+		eventWithCreator, err := h.EventRepository.GetEventWithCreator(eventID, userID, orgID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			http.Error(w, "Failed to fetch event with creator", http.StatusInternalServerError)
 			return
@@ -332,84 +878,264 @@ func (h *EventHandler) GetEventWithCreator() http.HandlerFunc {
 	}
 }
 
+// Accept принимает приглашение от имени самого участника
 func (h *EventHandler) Accept() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		//Проверка что только этот пользователь может принять приглашение
-		//добавить в список участников с приглашениями
-		//прописать json ответ в виде accept
+		h.respond(w, r, models.StatusAccepted)
+	}
+}
+
+// Decline отклоняет приглашение от имени самого участника
+func (h *EventHandler) Decline() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.respond(w, r, models.StatusDecline)
+	}
+}
+
+// respond сохраняет ответ участника на приглашение. Ответить может только сам участник
+// события своей организации; ответ и запись в истории сохраняются одной транзакцией.
+func (h *EventHandler) respond(w http.ResponseWriter, r *http.Request, status models.EventStatus) {
+	userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	eventId, err := convert.ParseId(r, "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	//парсим юзер ИД
+	userIdFromUrl, err := convert.ParseId(r, "userid")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	//только сам участник может принять или отклонить приглашение
+	if userId != userIdFromUrl {
+		http.Error(w, "Wrong user", http.StatusConflict)
+		return
+	}
+	orgID, _ := middleware.OrganizationID(r.Context())
+	foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+	if err != nil {
+		http.Error(w, "Event not found", http.StatusNotFound)
+		return
+	}
+	isParticipant, err := h.EventParticipant.IsParticipant(foundEvent.ID, userId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !isParticipant {
+		http.Error(w, "Event not found", http.StatusNotFound)
+		return
+	}
+
+	h, tx, err := h.begin()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+	if err := h.recordStatusChange(r, foundEvent.ID, userId, status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.EventParticipant.Respond(foundEvent.ID, userId, status); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit().Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.JsonResponse(w, &models.EventParticipant{EventID: foundEvent.ID, UserID: userId, Status: status}, http.StatusOK)
+}
 
+// refreshConferenceLink возвращает актуальную ссылку на видеовстречу после изменения события.
+// Ссылка удаляется, если видеовстреча больше не нужна, и создается заново при переносе встречи.
+func (h *EventHandler) refreshConferenceLink(ev *models.Event, title string, conferencing, rescheduled bool) (string, error) {
+	if ev.ConferenceLink != "" {
+		if conferencing && !rescheduled {
+			return ev.ConferenceLink, nil
+		}
+		if err := h.Conferencing.ReleaseMeeting(ev.ConferenceLink); err != nil {
+			return "", err
+		}
+	}
+	if !conferencing {
+		return "", nil
+	}
+	return h.Conferencing.CreateMeeting(title)
+}
+
+// FreeBusy возвращает занятые интервалы пользователя за период. Доступно самому пользователю
+// и тем, кому открыт его календарь на любом уровне
+func (h *EventHandler) FreeBusy() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
 		if !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
-		eventId, err := convert.ParseId(r, "id")
+		ownerId, err := convert.ParseId(r, "user_id")
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		//парсим юзер ИД
-		userIdFromUrl, err := convert.ParseId(r, "userid")
+		if ownerId != userId {
+			shareLevel, err := h.CalendarShares.LevelFor(ownerId, userId)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !shareLevel.Allows(models.ShareFreeBusy) {
+				http.Error(w, "Calendar is not shared with you", http.StatusForbidden)
+				return
+			}
+		}
+		from, err := request.ValidateTime(r.URL.Query().Get("from"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		//проверяем что юзер является участником события и только он может принять или отклонить
-		if userId != userIdFromUrl {
-			http.Error(w, "Wrong user", http.StatusConflict)
+		to, err := request.ValidateTime(r.URL.Query().Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-
-		//обновляем статус
-		updateStatus := &models.EventParticipant{
-			EventID: eventId,
-			UserID:  userId,
-			Status:  models.StatusAccepted,
+		if !to.After(from) {
+			http.Error(w, "to must be after from", http.StatusBadRequest)
+			return
 		}
-		updatedStatus, err := h.EventParticipant.UpdateParticipant(updateStatus)
+
+		busyEvents, err := h.EventRepository.FindBusyIntervals(ownerId, from, to)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Failed to fetch busy intervals", http.StatusInternalServerError)
 			return
 		}
+		intervals := make([]BusyInterval, 0, len(busyEvents))
+		for _, ev := range busyEvents {
+			intervals = append(intervals, BusyInterval{
+				Start: ev.StartDate,
+				End:   ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute),
+				Soft:  ev.Focus,
+			})
+		}
+		res.JsonResponse(w, FreeBusyResponse{
+			UserID: ownerId,
+			Busy:   intervals,
+		}, http.StatusOK)
+	}
+}
 
-		res.JsonResponse(w, updatedStatus, http.StatusOK)
-
+// checkCalendar проверяет, что календарь существует и принадлежит владельцу события
+func (h *EventHandler) checkCalendar(w http.ResponseWriter, calendarID *uint, ownerID uint) bool {
+	if calendarID == nil {
+		return true
+	}
+	foundCalendar, err := h.Calendars.FindById(*calendarID)
+	if err != nil {
+		http.Error(w, "Calendar not found", http.StatusBadRequest)
+		return false
 	}
+	if foundCalendar.OwnerID != ownerID {
+		http.Error(w, "Calendar belongs to another user", http.StatusForbidden)
+		return false
+	}
+	return true
 }
-func (h *EventHandler) Decline() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
-		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
-			return
+
+// invitee приглашенный пользователь, GroupID заполнен для приглашенных через группу
+type invitee struct {
+	InviteUsers
+	GroupID *uint
+}
+
+// expandGroups проверяет приглашенные группы и возвращает всех приглашенных без повторов.
+// Пользователи, указанные явно, сохраняют свою роль, создатель события не приглашается.
+func (h *EventHandler) expandGroups(w http.ResponseWriter, body *EventRequest) ([]invitee, bool) {
+	invitees := make([]invitee, 0, len(body.InvatedUsers))
+	seen := map[uint]bool{body.CreatorID: true}
+	for _, invUser := range body.InvatedUsers {
+		if seen[invUser.UserId] {
+			continue
 		}
-		eventId, err := convert.ParseId(r, "id")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+		seen[invUser.UserId] = true
+		invitees = append(invitees, invitee{InviteUsers: invUser})
+	}
+	for i := range body.InvitedGroups {
+		invGroup := &body.InvitedGroups[i]
+		if invGroup.Role == "" {
+			invGroup.Role = models.RoleRequired
 		}
-		//парсим юзер ИД
-		userIdFromUrl, err := convert.ParseId(r, "userid")
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
+		if !invGroup.Role.IsValidInviteRole() {
+			http.Error(w, "Invalid participant role", http.StatusBadRequest)
+			return nil, false
 		}
-		if userId != userIdFromUrl {
-			http.Error(w, "Wrong user", http.StatusConflict)
+		//пригласить группу может только ее участник
+		isMember, err := h.Groups.IsMember(invGroup.GroupID, body.CreatorID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return nil, false
 		}
-
-		//обновляем статус
-		updateStatus := &models.EventParticipant{
-			EventID: eventId,
-			UserID:  userId,
-			Status:  models.StatusDecline,
+		if !isMember {
+			http.Error(w, "Only group members can invite the group", http.StatusForbidden)
+			return nil, false
 		}
-		updatedStatus, err := h.EventParticipant.UpdateParticipant(updateStatus)
+		memberIDs, err := h.Groups.MemberIDs(invGroup.GroupID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+			return nil, false
+		}
+		for _, memberID := range memberIDs {
+			if seen[memberID] {
+				continue
+			}
+			seen[memberID] = true
+			invitees = append(invitees, invitee{
+				InviteUsers: InviteUsers{UserId: memberID, Role: invGroup.Role},
+				GroupID:     &invGroup.GroupID,
+			})
 		}
-		res.JsonResponse(w, updatedStatus, http.StatusOK)
+	}
+	return invitees, true
+}
 
+// inviteStatus определяет статус приглашения в событие eventID: отсутствующий пользователь отклоняет его
+// автоматически с сообщением об отсутствии, при пересечении с другими событиями он занят
+func (h *EventHandler) inviteStatus(userID uint, start time.Time, duration int, eventID uint) (models.EventStatus, string, error) {
+	end := start.Add(time.Duration(duration) * time.Minute)
+	period, err := h.OutOfOffice.FindOverlapping(userID, start, end)
+	if err != nil {
+		return "", "", err
+	}
+	if period != nil {
+		return models.StatusOutOfOffice, period.Message, nil
+	}
+	//фокус-время не мешает приглашению, фоновая задача перенесет блок
+	level, err := h.EventRepository.IsUserBusy(userID, start, duration, eventID)
+	if err != nil {
+		return "", "", err
+	}
+	if level == models.BusyHard {
+		return models.StatusBusy, "", nil
+	}
+	return models.StatusAccepted, "", nil
+}
+
+// holidayWarnings возвращает предупреждения для участников, у которых событие приходится на праздник
+func (h *EventHandler) holidayWarnings(userIDs []uint, start time.Time, duration int) ([]string, error) {
+	end := start.Add(time.Duration(duration) * time.Minute)
+	userHolidays, err := h.Holidays.HolidaysFor(userIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, day := range userHolidays {
+		warnings = append(warnings, fmt.Sprintf("%s is a holiday for user %d: %s",
+			day.Date.Format(holidays.DateLayout), day.UserID, day.Name))
 	}
+	return warnings, nil
 }
@@ -0,0 +1,71 @@
+package event
+
+import (
+	"net/http"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+)
+
+// GetHistory возвращает историю изменений события. Доступна тем, кто видит подробности события.
+func (h *EventHandler) GetHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		access, err := h.eventAccess([]models.Event{*foundEvent}, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !access[foundEvent.ID].Allows(models.ShareDetails) {
+			http.Error(w, "Event history not available", http.StatusForbidden)
+			return
+		}
+		revisions, err := h.History.FindByEvent(foundEvent.ID)
+		if err != nil {
+			http.Error(w, "Failed to fetch event history", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, revisions, http.StatusOK)
+	}
+}
+
+// participantStatuses возвращает текущие статусы участников события
+func (h *EventHandler) participantStatuses(eventID uint, userIDs []uint) (map[uint]models.EventStatus, error) {
+	participations, err := h.EventParticipant.FindParticipations([]uint{eventID}, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[uint]models.EventStatus, len(participations))
+	for _, p := range participations {
+		statuses[p.UserID] = p.Status
+	}
+	return statuses, nil
+}
+
+// recordStatusChange записывает в историю ответ участника на приглашение
+func (h *EventHandler) recordStatusChange(r *http.Request, eventID, userID uint, status models.EventStatus) error {
+	statuses, err := h.participantStatuses(eventID, []uint{userID})
+	if err != nil {
+		return err
+	}
+	return h.History.RecordChange(r.Context(), eventID, models.RevisionParticipantStatus, models.FieldChanges{
+		models.ParticipantField(userID, "status"): {From: statuses[userID], To: status},
+	})
+}
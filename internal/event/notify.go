@@ -0,0 +1,69 @@
+package event
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+)
+
+// mentionPattern упоминание пользователя в тексте события: @username
+var mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.\-]+)`)
+
+// eventNotice данные события для писем и организатор события.
+// Если организатор не нашелся, письма уходят без его имени.
+func (h *EventHandler) eventNotice(ev *models.Event) (notifier.EventDetails, *models.User) {
+	organizer, err := h.UserRepository.FindByid(ev.CreatorID)
+	if err != nil {
+		organizer = nil
+	}
+	return notifier.NewEventDetails(ev, organizer), organizer
+}
+
+// guestRecipient у гостя нет учетной записи, письма ему приходят на языке и в часовом поясе организатора
+func guestRecipient(guest models.EventGuest, organizer *models.User) notifier.Recipient {
+	to := notifier.Recipient{Email: guest.Email, Name: guest.Name}
+	if organizer != nil {
+		to.Locale = organizer.Locale
+		to.Timezone = organizer.Timezone
+	}
+	return to
+}
+
+// mentionedUsernames имена, упомянутые в описании, повестке и протоколе события, в нижнем регистре
+func mentionedUsernames(ev *models.Event) map[string]bool {
+	names := map[string]bool{}
+	for _, text := range []string{ev.Description, ev.Agenda, ev.Minutes} {
+		for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			//точка или дефис в конце относятся к предложению, а не к имени
+			names[strings.ToLower(strings.TrimRight(match[1], ".-"))] = true
+		}
+	}
+	return names
+}
+
+// notifyMentions уведомляет участников, упомянутых в тексте события после изменения.
+// Упоминания, которые были и до него (before), и упоминание автора изменения не уведомляются.
+// Пользователи не из числа участников события уведомление не получают: им подробности не видны.
+func (h *EventHandler) notifyMentions(ctx context.Context, before, after *models.Event, participants []models.User, actorID uint, details notifier.EventDetails) error {
+	mentioned := mentionedUsernames(after)
+	if len(mentioned) == 0 {
+		return nil
+	}
+	previous := map[string]bool{}
+	if before != nil {
+		previous = mentionedUsernames(before)
+	}
+	for _, participant := range participants {
+		name := strings.ToLower(participant.Username)
+		if participant.ID == actorID || !mentioned[name] || previous[name] {
+			continue
+		}
+		if err := h.Notifier.Notify(ctx, notifier.Mentioned(notifier.UserRecipient(&participant), details)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
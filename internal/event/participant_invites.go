@@ -0,0 +1,35 @@
+package event
+
+import (
+	"context"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+)
+
+// ParticipantInviter отправляет приглашение участнику, которого организатор добавил в событие напрямую
+type ParticipantInviter struct {
+	handler *EventHandler
+}
+
+// NewParticipantInviter создает приглашающего с зависимостями обработчика событий
+func NewParticipantInviter(deps EventHandlerDeps) *ParticipantInviter {
+	return &ParticipantInviter{handler: newEventHandler(deps)}
+}
+
+// SendInvitation записывает приглашение со ссылками для ответа в outbox транзакции tx,
+// поэтому оно уйдет, только если участник будет сохранен
+func (p *ParticipantInviter) SendInvitation(ctx context.Context, tx *db.Db, eventID, userID uint) error {
+	h := p.handler.withTx(tx)
+	ev, err := h.EventRepository.FindById(eventID)
+	if err != nil {
+		return err
+	}
+	member, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		return err
+	}
+	details, _ := h.eventNotice(ev)
+	acceptLink, declineLink := h.rsvpLinks(ev, userID, 0)
+	return h.Notifier.Notify(ctx, notifier.Invited(notifier.UserRecipient(member), details, acceptLink, declineLink))
+}
@@ -1,21 +1,64 @@
 package event
 
-import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+)
 
 // приглашенные пользователи
 type InviteUsers struct {
-	UserName string `json:"user_name"`
-	UserId   uint   `json:"user_id"`
+	UserName string                 `json:"user_name"`
+	UserId   uint                   `json:"user_id"`
+	Role     models.ParticipantRole `json:"role"`
+	// Email приглашение по адресу: если в организации нет такого пользователя, приглашается внешний гость
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+// приглашенные группы, участники группы приглашаются по одному
+type InviteGroup struct {
+	GroupID uint                   `json:"group_id" validate:"required"`
+	Role    models.ParticipantRole `json:"role"`
+	// IncludeNewMembers новые участники группы будут добавлены в событие, пока оно не началось
+	IncludeNewMembers bool `json:"include_new_members"`
 }
 
 // EventRequest представляет данные для создания или обновления события
 type EventRequest struct {
-	Title        string        `json:"title" validate:"required"`
-	Description  string        `json:"description"`
-	StartDate    string        `json:"start_date" `
-	Duration     int           `json:"duration"`
-	CreatorID    uint          `json:"creator_id" validate:"required"`
-	InvatedUsers []InviteUsers `json:"invated_users"`
+	Title       string `json:"title" validate:"required"`
+	Description string `json:"description"`
+	// Agenda повестка встречи
+	Agenda string `json:"agenda"`
+	// Minutes протокол встречи
+	Minutes       string        `json:"minutes"`
+	StartDate     string        `json:"start_date" `
+	Duration      int           `json:"duration"`
+	CreatorID     uint          `json:"creator_id" validate:"required"`
+	InvatedUsers  []InviteUsers `json:"invated_users" validate:"dive"`
+	InvitedGroups []InviteGroup `json:"invited_groups" validate:"dive"`
+	Conferencing  bool          `json:"conferencing"`
+	CalendarID    *uint         `json:"calendar_id"`
+	// NotifyParticipants отправить участникам уведомление, если изменено только описание события
+	NotifyParticipants bool `json:"notify_participants"`
+	// TagIDs метки события, учитываются при создании
+	TagIDs []uint `json:"tag_ids"`
+	// SeriesID событие, к серии которого относится новое событие
+	SeriesID *uint `json:"series_id"`
+}
+
+// ClaimGuestsRequest ссылка из письма, полученного гостем, подтверждает владение его email
+type ClaimGuestsRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ClaimGuestsResponse события, в которые пользователь добавлен вместо гостя
+type ClaimGuestsResponse struct {
+	EventIDs []uint `json:"event_ids"`
+}
+
+// TagsRequest новый набор меток события
+type TagsRequest struct {
+	TagIDs []uint `json:"tag_ids"`
 }
 
 // EventResponse представляет данные для ответа о событии
@@ -24,8 +67,105 @@ type EventResponse struct {
 	Description string `json:"description"`
 	StartDate   string `json:"start_date" `
 	Duration    int    `json:"duration"`
-	Status      []models.UserStatus
+	// ConferenceLink ссылка на видеовстречу, если она была запрошена
+	ConferenceLink string `json:"conference_link,omitempty"`
+	Status         []models.UserStatus
+	// OptionalStatus занятость необязательных участников
+	OptionalStatus []models.UserStatus `json:"optional_status,omitempty"`
+	// Guests внешние гости, приглашенные по email
+	Guests []models.EventGuest `json:"guests,omitempty"`
+	// Warnings предупреждения, которые не мешают сохранить событие, например о праздниках
+	Warnings []string `json:"warnings,omitempty"`
+	// Reinvited участники, которым приглашение отправлено заново после переноса события
+	Reinvited []uint `json:"reinvited"`
 }
 type DeleteResponse struct {
 	Delete bool `json:"delete"`
 }
+
+// BusyInterval занятый интервал в календаре пользователя
+type BusyInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Soft блок фокус-времени, который можно занять встречей
+	Soft bool `json:"soft,omitempty"`
+}
+
+// FreeBusyResponse занятость пользователя за период
+type FreeBusyResponse struct {
+	UserID uint           `json:"user_id"`
+	Busy   []BusyInterval `json:"busy"`
+}
+
+// SuggestSlotsRequest участники и длительность встречи, для которой подбирается время
+type SuggestSlotsRequest struct {
+	InvatedUsers []InviteUsers `json:"invated_users" validate:"dive"`
+	Duration     int           `json:"duration" validate:"required,min=1"`
+	From         string        `json:"from" validate:"required"`
+	To           string        `json:"to" validate:"required"`
+}
+
+// SuggestedSlot время, когда свободны организатор и все обязательные участники
+type SuggestedSlot struct {
+	models.TimeSlot
+	// OptionalBusy необязательные участники, занятые в это время
+	OptionalBusy []uint `json:"optional_busy,omitempty"`
+}
+
+// SuggestSlotsResponse подходящее время встречи, начиная с ближайшего
+type SuggestSlotsResponse struct {
+	Slots []SuggestedSlot `json:"slots"`
+}
+
+// AttendanceRequest отметка организатора о присутствии участника
+type AttendanceRequest struct {
+	Attended bool `json:"attended"`
+}
+
+// EventAttendanceResponse присутствие участников события
+type EventAttendanceResponse struct {
+	models.AttendanceSummary
+	Rate         float64                        `json:"rate"`
+	Participants []models.ParticipantAttendance `json:"participants"`
+}
+
+// SeriesAttendanceResponse присутствие по прошедшим событиям серии
+type SeriesAttendanceResponse struct {
+	SeriesID uint                       `json:"series_id"`
+	Accepted int                        `json:"accepted"`
+	Attended int                        `json:"attended"`
+	NoShows  int                        `json:"no_shows"`
+	Rate     float64                    `json:"rate"`
+	Events   []models.AttendanceSummary `json:"events"`
+}
+
+// FeedbackRequest ответ участника на опрос после встречи
+type FeedbackRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Needed  *bool  `json:"needed" validate:"required"`
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+// FeedbackComment комментарий участника без указания автора
+type FeedbackComment struct {
+	EventID     uint      `json:"event_id"`
+	Comment     string    `json:"comment"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// EventFeedbackResponse сводка опроса по событию
+type EventFeedbackResponse struct {
+	models.FeedbackSummary
+	Comments []FeedbackComment `json:"comments"`
+}
+
+// SeriesFeedbackResponse сводка опросов по прошедшим событиям серии
+type SeriesFeedbackResponse struct {
+	SeriesID    uint                     `json:"series_id"`
+	Sent        int                      `json:"sent"`
+	Responses   int                      `json:"responses"`
+	AvgRating   float64                  `json:"avg_rating"`
+	NeededShare float64                  `json:"needed_share"`
+	Events      []models.FeedbackSummary `json:"events"`
+	Comments    []FeedbackComment        `json:"comments"`
+}
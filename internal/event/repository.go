@@ -1,12 +1,17 @@
 package event
 
 import (
+	"errors"
 	"time"
 
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
 )
 
+// ErrVersionConflict событие изменено другим запросом после чтения
+var ErrVersionConflict = errors.New("event was modified by another request")
+
 type EventRepository struct {
 	DataBase *db.Db
 }
@@ -39,6 +44,19 @@ func (repo *EventRepository) FindById(id uint) (*models.Event, error) {
 	return &event, nil
 }
 
+// FindInOrganization находит событие по ID только внутри указанной организации
+func (repo *EventRepository) FindInOrganization(id, organizationID uint) (*models.Event, error) {
+	var event models.Event
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("organization_id = ?", organizationID).
+		First(&event, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &event, nil
+}
+
 // FindAllByCreatorId находит все события, созданные пользователем с указанным ID
 func (repo *EventRepository) FindAllByCreatorId(id uint) ([]models.Event, error) {
 	var events []models.Event
@@ -59,6 +77,64 @@ func (repo *EventRepository) Update(event *models.Event) (*models.Event, error)
 	return event, nil
 }
 
+// UpdateIfVersion обновляет редактируемые поля события, только если в БД все еще хранится
+// ожидаемая версия. Версия увеличивается в том же запросе, поэтому проверка не зависит от гонок.
+func (repo *EventRepository) UpdateIfVersion(event *models.Event, version int) (*models.Event, error) {
+	event.Version = version + 1
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(event).
+		Where("version = ?", version).
+		Select("title", "description", "agenda", "minutes", "start_date", "duration", "calendar_id", "conference_link", "version").
+		Updates(event)
+	if result.Error != nil {
+		event.Version = version
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		event.Version = version
+		return nil, ErrVersionConflict
+	}
+	return event, nil
+}
+
+// BumpVersion увеличивает версию события, когда меняется его состав участников,
+// чтобы клиенты с устаревшим ETag получили конфликт
+func (repo *EventRepository) BumpVersion(eventID uint) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
+// SetSeries относит событие к серии
+func (repo *EventRepository) SetSeries(eventID, seriesID uint) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("series_id", seriesID)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// FindSeries возвращает события серии в организации в порядке начала
+func (repo *EventRepository) FindSeries(seriesID, organizationID uint) ([]models.Event, error) {
+	var events []models.Event
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("series_id = ? AND organization_id = ?", seriesID, organizationID).
+		Order("start_date").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
 // DeleteById удаляет событие по его ID из базы данных
 func (repo *EventRepository) DeleteById(id uint) error {
 	result := repo.DataBase.DB.Delete(&models.Event{}, id)
@@ -68,11 +144,30 @@ func (repo *EventRepository) DeleteById(id uint) error {
 	return nil
 }
 
-// GetEventWithCreator получает событие вместе с информацией о создателе
-func (repo *EventRepository) GetEventWithCreator(eventID, userID uint) (*models.Event, error) {
+// DeleteIfVersion удаляет событие, только если его версия не изменилась.
+// Ссылка на видеовстречу очищается вместе с удалением.
+func (repo *EventRepository) DeleteIfVersion(id uint, version int) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Where("id = ? AND version = ?", id, version).
+		Updates(map[string]any{"deleted_at": time.Now(), "conference_link": ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// GetEventWithCreator получает событие организации вместе с информацией о создателе
+func (repo *EventRepository) GetEventWithCreator(eventID, userID, organizationID uint) (*models.Event, error) {
 	var event models.Event
 
-	result := repo.DataBase.DB.Preload("Creator").Where("id = ? AND creator_id = ?", eventID, userID).First(&event)
+	result := repo.DataBase.DB.Preload("Creator").
+		Where("id = ? AND creator_id = ? AND organization_id = ?", eventID, userID, organizationID).
+		First(&event)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -90,20 +185,181 @@ func (repo *EventRepository) GetEventsWithCreators() ([]models.Event, error) {
 	return events, nil
 }
 
-// ищем пересекающиеся события
-func (r *EventRepository) IsUserBusy(userID uint, start time.Time, duration int) bool {
-	end := start.Add(time.Duration(duration) * time.Minute)
-	var count int64
+// FindVisibleWithCreators получает события пользователя и события из открытых ему календарей:
+// созданные владельцами или с их участием, в пределах организации пользователя.
+// Если tagID не равен нулю, возвращаются только события с этой меткой.
+func (repo *EventRepository) FindVisibleWithCreators(userID, organizationID uint, sharedOwnerIDs []uint, tagID uint) ([]models.Event, error) {
+	var events []models.Event
+	result := repo.visible(userID, organizationID, sharedOwnerIDs, tagID).
+		Preload("Creator").
+		Preload("Tags").
+		Order("start_date").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// FindVisibleRefs возвращает те же события, что и FindVisibleWithCreators, но только поля,
+// нужные для проверки доступа: ID, создателя и календарь
+func (repo *EventRepository) FindVisibleRefs(userID, organizationID uint, sharedOwnerIDs []uint, tagID uint) ([]models.Event, error) {
+	var events []models.Event
+	result := repo.visible(userID, organizationID, sharedOwnerIDs, tagID).
+		Select("id", "creator_id", "calendar_id").
+		Order("start_date, id").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// visible строит запрос событий, созданных пользователем и владельцами открытых ему календарей
+// или с их участием, с необязательным фильтром по метке
+func (repo *EventRepository) visible(userID, organizationID uint, sharedOwnerIDs []uint, tagID uint) *gorm.DB {
+	userIDs := append([]uint{userID}, sharedOwnerIDs...)
+	participations := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Select("event_id").
+		Where("user_id IN ?", userIDs)
+
+	query := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("organization_id = ?", organizationID).
+		Where("creator_id IN ? OR id IN (?)", userIDs, participations)
+	if tagID != 0 {
+		query = query.Where("id IN (?)", repo.DataBase.DB.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&models.EventTag{}).
+			Select("event_id").
+			Where("tag_id = ?", tagID))
+	}
+	return query
+}
+
+// StreamAttendance построчно читает участников событий вместе с данными события и организатора
+// и передает строки в fn, не загружая весь результат в память. Строки упорядочены по началу события.
+func (repo *EventRepository) StreamAttendance(eventIDs []uint, fn func(row *models.AttendanceRow) error) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	rows, err := db.
+		Table("events e").
+		Select("e.id AS event_id, e.title, e.start_date, e.duration, creator.username AS organizer, "+
+			"COALESCE(u.username, '') AS participant, COALESCE(u.email, '') AS participant_email, "+
+			"COALESCE(ep.role, '') AS role, COALESCE(ep.status, '') AS status, ep.responded_at").
+		Joins("JOIN users creator ON creator.id = e.creator_id").
+		Joins("LEFT JOIN event_participants ep ON ep.event_id = e.id AND ep.deleted_at IS NULL").
+		Joins("LEFT JOIN users u ON u.id = ep.user_id").
+		Where("e.id IN ? AND e.deleted_at IS NULL", eventIDs).
+		Order("e.start_date, e.id, u.username").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var row models.AttendanceRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Search ищет по названию, описанию, повестке и протоколу событий, подробности которых видны пользователю:
+// своих, событий, где он участник, и событий владельцев detailOwnerIDs, открывших ему подробности
+// календаря, кроме событий приватных календарей. Доступ проверяется в запросе, поэтому limit
+// применяется к уже отфильтрованной выдаче. Запрос разбирается в русской и английской конфигурациях,
+// результаты упорядочены по релевантности.
+func (repo *EventRepository) Search(userID, organizationID uint, detailOwnerIDs []uint, query string, limit int) ([]models.EventSearchHit, error) {
+	participations := func(userIDs any) *gorm.DB {
+		return repo.DataBase.DB.
+			Session(&gorm.Session{NewDB: true}).
+			Model(&models.EventParticipant{}).
+			Select("event_id").
+			Where("user_id IN ?", userIDs)
+	}
+	//подсветка строится в конфигурации того языка, запрос на котором совпал с текстом
+	const document = "concat_ws(' ', events.title, events.description, events.agenda, events.minutes)"
+	const headlineOptions = "'StartSel=<mark>, StopSel=</mark>, MaxFragments=2'"
 
-	r.DataBase.DB.
+	var hits []models.EventSearchHit
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("events").
+		Joins("CROSS JOIN (SELECT websearch_to_tsquery('russian', ?) AS ru, websearch_to_tsquery('english', ?) AS en) q", query, query).
+		Select("events.*, ts_rank(events.search_vector, q.ru || q.en) AS rank, "+
+			"CASE WHEN to_tsvector('russian', "+document+") @@ q.ru "+
+			"THEN ts_headline('russian', "+document+", q.ru, "+headlineOptions+") "+
+			"ELSE ts_headline('english', "+document+", q.en, "+headlineOptions+") END AS headline").
+		Where("events.deleted_at IS NULL AND events.organization_id = ?", organizationID).
+		Where("events.creator_id = ? OR events.id IN (?) OR "+
+			"((events.creator_id IN ? OR events.id IN (?)) AND NOT EXISTS "+
+			"(SELECT 1 FROM calendars c WHERE c.id = events.calendar_id AND c.deleted_at IS NULL AND c.default_visibility = ?))",
+			userID, participations([]uint{userID}), detailOwnerIDs, participations(detailOwnerIDs), models.VisibilityPrivate).
+		Where("events.search_vector @@ (q.ru || q.en)").
+		Order("rank DESC, events.start_date DESC").
+		Limit(limit).
+		Scan(&hits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return hits, nil
+}
+
+// FindBusyIntervals возвращает события пользователя, пересекающиеся с периодом [from, to)
+func (repo *EventRepository) FindBusyIntervals(userID uint, from, to time.Time) ([]models.Event, error) {
+	var events []models.Event
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
 		Model(&models.Event{}).
-		Joins("JOIN event_participants ep ON ep.event_id = events.id").
+		Joins("JOIN event_participants ep ON ep.event_id = events.id AND ep.deleted_at IS NULL").
+		Joins("LEFT JOIN calendars c ON c.id = events.calendar_id AND c.deleted_at IS NULL").
 		Where("ep.user_id = ?", userID).
+		Where("c.id IS NULL OR c.counts_toward_busy").
 		Where(`
 			(events.start_date, events.start_date + (events.duration || ' minutes')::interval)
 			OVERLAPS (?, ?)`,
-			start, end).
-		Count(&count)
+			from, to).
+		Order("events.start_date").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// ищем пересекающиеся события, на которые пользователь не ответил отказом. Собственные календари
+// пользователя учитываются, только если влияют на занятость; события из чужих календарей занимают всегда.
+// Блоки фокус-времени дают только мягкую занятость. Событие excludeEventID не учитывается,
+// чтобы перенесенная встреча не пересекалась сама с собой; 0 — учитывать все события.
+func (r *EventRepository) IsUserBusy(userID uint, start time.Time, duration int, excludeEventID uint) (models.BusyLevel, error) {
+	end := start.Add(time.Duration(duration) * time.Minute)
+	var level models.BusyLevel
 
-	return count > 0
+	result := r.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Select("COALESCE(MAX(CASE WHEN events.focus THEN ? ELSE ? END), ?)", models.BusySoft, models.BusyHard, models.BusyFree).
+		Joins("JOIN event_participants ep ON ep.event_id = events.id AND ep.deleted_at IS NULL").
+		Joins("LEFT JOIN calendars c ON c.id = events.calendar_id AND c.owner_id = ep.user_id AND c.deleted_at IS NULL").
+		Where("ep.user_id = ? AND events.id <> ?", userID, excludeEventID).
+		Where("ep.status NOT IN ?", []models.EventStatus{models.StatusDecline, models.StatusOutOfOffice}).
+		Where("c.id IS NULL OR c.counts_toward_busy").
+		Where(`
+			(events.start_date, events.start_date + (events.duration || ' minutes')::interval)
+			OVERLAPS (?, ?)`,
+			start, end).
+		Scan(&level)
+	if result.Error != nil {
+		return models.BusyFree, result.Error
+	}
+	return level, nil
 }
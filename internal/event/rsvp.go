@@ -0,0 +1,205 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/rsvp"
+	"github.com/go-chi/chi/v5"
+)
+
+// rsvpPage страница, которую видит получатель после перехода по ссылке из письма
+var rsvpPage = template.Must(template.New("rsvp").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head><meta charset="utf-8"><title>{{.Heading}}</title></head>
+<body>
+<h1>{{.Heading}}</h1>
+<p>{{.Message}}</p>
+{{if .Title}}<p><strong>{{.Title}}</strong><br>{{.Start}}</p>{{end}}
+</body>
+</html>
+`))
+
+type rsvpPageData struct {
+	Lang    string
+	Heading string
+	Message string
+	Title   string
+	Start   string
+}
+
+// rsvpTexts тексты страницы подтверждения на одном языке
+type rsvpTexts struct {
+	Expired    rsvpPageData
+	Invalid    rsvpPageData
+	Outdated   rsvpPageData
+	NotFound   rsvpPageData
+	Failed     rsvpPageData
+	Accepted   rsvpPageData
+	Declined   rsvpPageData
+	DateLayout string
+}
+
+// rsvpLocales тексты страницы подтверждения на языках писем
+var rsvpLocales = map[string]rsvpTexts{
+	"ru": {
+		Expired:    rsvpPageData{Heading: "Ссылка устарела", Message: "Срок действия ссылки приглашения истек."},
+		Invalid:    rsvpPageData{Heading: "Неверная ссылка", Message: "Ссылка приглашения недействительна."},
+		Outdated:   rsvpPageData{Heading: "Встреча перенесена", Message: "Время встречи изменилось. Воспользуйтесь ссылкой из последнего приглашения."},
+		NotFound:   rsvpPageData{Heading: "Приглашение не найдено", Message: "Событие отменено, или вы больше не приглашены."},
+		Failed:     rsvpPageData{Heading: "Что-то пошло не так", Message: "Не удалось сохранить ответ. Попробуйте еще раз позже."},
+		Accepted:   rsvpPageData{Heading: "Приглашение принято", Message: "До встречи!"},
+		Declined:   rsvpPageData{Heading: "Приглашение отклонено", Message: "Ваш ответ сохранен."},
+		DateLayout: "02.01.2006 15:04 MST",
+	},
+	"en": {
+		Expired:    rsvpPageData{Heading: "Link expired", Message: "This invitation link is no longer valid."},
+		Invalid:    rsvpPageData{Heading: "Invalid link", Message: "This invitation link is not valid."},
+		Outdated:   rsvpPageData{Heading: "Meeting rescheduled", Message: "The meeting time has changed. Please use the link from the latest invitation."},
+		NotFound:   rsvpPageData{Heading: "Invitation not found", Message: "The event was cancelled or you are no longer invited."},
+		Failed:     rsvpPageData{Heading: "Something went wrong", Message: "We could not save your response. Please try again later."},
+		Accepted:   rsvpPageData{Heading: "Invitation accepted", Message: "See you at the meeting."},
+		Declined:   rsvpPageData{Heading: "Invitation declined", Message: "Your response has been saved."},
+		DateLayout: "Mon, 02 Jan 2006 15:04 MST",
+	},
+}
+
+// rsvpKey ключ подписи ссылок ответа, выведенный из секрета сервера
+func (h *EventHandler) rsvpKey() string {
+	return rsvp.DeriveKey(h.Config.Auth.Secret)
+}
+
+// rsvpLinks выпускает подписанные ссылки принятия и отклонения приглашения для пользователя
+// или внешнего гостя. Ссылки действуют до окончания события и только для текущего времени его начала.
+func (h *EventHandler) rsvpLinks(ev *models.Event, userID, guestID uint) (accept, decline string) {
+	claims := rsvp.Claims{
+		EventID: ev.ID,
+		UserID:  userID,
+		GuestID: guestID,
+		Start:   ev.StartDate,
+		Expires: ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute),
+	}
+	base := h.Config.Public.BaseURL + "/rsvp/"
+	claims.Action = rsvp.ActionAccept
+	accept = base + rsvp.Sign(h.rsvpKey(), claims)
+	claims.Action = rsvp.ActionDecline
+	decline = base + rsvp.Sign(h.rsvpKey(), claims)
+	return accept, decline
+}
+
+// RSVP принимает или отклоняет приглашение по подписанной ссылке из письма и показывает страницу подтверждения.
+// Страница выводится на языке получателя-пользователя, для гостя - на языке браузера.
+func (h *EventHandler) RSVP() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locale := requestLocale(r)
+		claims, err := rsvp.Verify(h.rsvpKey(), chi.URLParam(r, "token"), time.Now())
+		if errors.Is(err, rsvp.ErrExpiredToken) {
+			renderRSVP(w, http.StatusGone, locale, rsvpLocales[locale].Expired)
+			return
+		}
+		if err != nil {
+			renderRSVP(w, http.StatusBadRequest, locale, rsvpLocales[locale].Invalid)
+			return
+		}
+		location := time.UTC
+		if claims.UserID != 0 {
+			if recipient, err := h.UserRepository.FindByid(claims.UserID); err == nil {
+				locale = notifier.ResolveLocale(recipient.Locale)
+				location = recipient.Location()
+			}
+		}
+		texts := rsvpLocales[locale]
+		//страница публичная: подробности ошибки остаются в журнале
+		failed := func(err error) {
+			log.Printf("RSVP: event_id=%d, user_id=%d, guest_id=%d: %v", claims.EventID, claims.UserID, claims.GuestID, err)
+			renderRSVP(w, http.StatusInternalServerError, locale, texts.Failed)
+		}
+		foundEvent, err := h.EventRepository.FindById(claims.EventID)
+		if err != nil {
+			renderRSVP(w, http.StatusNotFound, locale, texts.NotFound)
+			return
+		}
+		//ссылка выпущена до переноса события: ответ относился к другому времени
+		if claims.Start.Unix() != foundEvent.StartDate.Unix() {
+			renderRSVP(w, http.StatusGone, locale, texts.Outdated)
+			return
+		}
+		status := models.StatusAccepted
+		if claims.Action == rsvp.ActionDecline {
+			status = models.StatusDecline
+		}
+
+		if claims.UserID != 0 {
+			isParticipant, err := h.EventParticipant.IsParticipant(foundEvent.ID, claims.UserID)
+			if err != nil {
+				failed(err)
+				return
+			}
+			if !isParticipant {
+				renderRSVP(w, http.StatusNotFound, locale, texts.NotFound)
+				return
+			}
+			//ответ по ссылке записывается в историю от имени получателя
+			r = r.WithContext(context.WithValue(r.Context(), middleware.ContextUserIDKey, claims.UserID))
+			h, tx, err := h.begin()
+			if err != nil {
+				failed(err)
+				return
+			}
+			defer tx.Rollback()
+			if err := h.recordStatusChange(r, foundEvent.ID, claims.UserID, status); err != nil {
+				failed(err)
+				return
+			}
+			if err := h.EventParticipant.Respond(foundEvent.ID, claims.UserID, status); err != nil {
+				failed(err)
+				return
+			}
+			if err := tx.Commit().Error; err != nil {
+				failed(err)
+				return
+			}
+		} else {
+			guest, err := h.Guests.FindById(claims.GuestID)
+			//гость мог стать пользователем, тогда его приглашение уже перенесено
+			if err != nil || guest.EventID != foundEvent.ID {
+				renderRSVP(w, http.StatusNotFound, locale, texts.NotFound)
+				return
+			}
+			if err := h.Guests.Respond(guest.ID, status, time.Now().UTC()); err != nil {
+				failed(err)
+				return
+			}
+		}
+
+		data := texts.Accepted
+		if status == models.StatusDecline {
+			data = texts.Declined
+		}
+		data.Title = foundEvent.Title
+		data.Start = foundEvent.StartDate.In(location).Format(texts.DateLayout)
+		renderRSVP(w, http.StatusOK, locale, data)
+	}
+}
+
+// requestLocale язык страницы по первому языку из Accept-Language
+func requestLocale(r *http.Request) string {
+	preferred, _, _ := strings.Cut(r.Header.Get("Accept-Language"), ",")
+	preferred, _, _ = strings.Cut(preferred, ";")
+	return notifier.ResolveLocale(strings.TrimSpace(preferred))
+}
+
+func renderRSVP(w http.ResponseWriter, status int, locale string, data rsvpPageData) {
+	data.Lang = locale
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	rsvpPage.Execute(w, data)
+}
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+)
+
+// SearchEvents полнотекстовый поиск по событиям, доступным пользователю.
+// События, которые пользователю видны только как занятость, в выдачу не попадают.
+func (h *EventHandler) SearchEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			http.Error(w, "Search query is required", http.StatusBadRequest)
+			return
+		}
+		limit := 20
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			limitInt, err := strconv.Atoi(limitStr)
+			if err != nil || limitInt < 1 || limitInt > 100 {
+				http.Error(w, "Invalid limit param, max 100 value", http.StatusBadRequest)
+				return
+			}
+			limit = limitInt
+		}
+
+		//совпадение по тексту раскрывает подробности, поэтому чужие календари учитываются,
+		//только если их подробности открыты пользователю
+		sharedOwners, err := h.CalendarShares.SharedOwners(userId)
+		if err != nil {
+			http.Error(w, "Failed to fetch calendar shares", http.StatusInternalServerError)
+			return
+		}
+		detailOwnerIDs := make([]uint, 0, len(sharedOwners))
+		for ownerID, level := range sharedOwners {
+			if level.Allows(models.ShareDetails) {
+				detailOwnerIDs = append(detailOwnerIDs, ownerID)
+			}
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		found, err := h.EventRepository.Search(userId, orgID, detailOwnerIDs, query, limit)
+		if err != nil {
+			http.Error(w, "Failed to search events", http.StatusInternalServerError)
+			return
+		}
+		if found == nil {
+			found = []models.EventSearchHit{}
+		}
+		res.JsonResponse(w, found, http.StatusOK)
+	}
+}
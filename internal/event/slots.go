@@ -0,0 +1,173 @@
+package event
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+)
+
+const (
+	// slotStep шаг, по которому выравниваются предлагаемые начала встречи
+	slotStep = 30 * time.Minute
+	// maxSuggestions сколько вариантов времени возвращается
+	maxSuggestions = 10
+	// maxSuggestRange самый длинный период подбора времени
+	maxSuggestRange = 14 * 24 * time.Hour
+)
+
+// SuggestSlots подбирает время встречи в рабочие часы организатора, когда свободны он сам и все
+// обязательные участники. Необязательные участники время не блокируют: у каждого варианта
+// перечислены те из них, кто в это время занят или отсутствует.
+func (h *EventHandler) SuggestSlots() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := request.HandelBody[SuggestSlotsRequest](w, r)
+		if err != nil {
+			http.Error(w, "Неверный запрос", http.StatusBadRequest)
+			return
+		}
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		from, err := request.ValidateTime(body.From)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to, err := request.ValidateTime(body.To)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !to.After(from) {
+			http.Error(w, "to must be after from", http.StatusBadRequest)
+			return
+		}
+		if to.Sub(from) > maxSuggestRange {
+			http.Error(w, "Search period is too long", http.StatusBadRequest)
+			return
+		}
+		organizer, err := h.UserRepository.FindByid(userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		//организатор участвует во встрече всегда, гости по email календаря не имеют
+		required := []uint{userId}
+		var optional []uint
+		for _, invUser := range body.InvatedUsers {
+			if invUser.UserId == 0 || invUser.UserId == userId {
+				continue
+			}
+			if _, err := h.UserRepository.FindInOrganization(invUser.UserId, orgID); err != nil {
+				http.Error(w, "User not found", http.StatusBadRequest)
+				return
+			}
+			if invUser.Role == models.RoleOptional {
+				optional = append(optional, invUser.UserId)
+			} else {
+				required = append(required, invUser.UserId)
+			}
+		}
+		busy := make(map[uint][]models.TimeSlot, len(required)+len(optional))
+		for _, id := range append(required, optional...) {
+			slots, err := h.busySlots(id, from, to)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			busy[id] = slots
+		}
+
+		suggestions := []SuggestedSlot{}
+		for _, slot := range candidateSlots(from, to, body.Duration, organizer.Location()) {
+			free := true
+			for _, id := range required {
+				if overlapsAny(slot, busy[id]) {
+					free = false
+					break
+				}
+			}
+			if !free {
+				continue
+			}
+			suggestion := SuggestedSlot{TimeSlot: slot}
+			for _, id := range optional {
+				if overlapsAny(slot, busy[id]) {
+					suggestion.OptionalBusy = append(suggestion.OptionalBusy, id)
+				}
+			}
+			suggestions = append(suggestions, suggestion)
+			if len(suggestions) == maxSuggestions {
+				break
+			}
+		}
+		res.JsonResponse(w, SuggestSlotsResponse{Slots: suggestions}, http.StatusOK)
+	}
+}
+
+// busySlots занятость пользователя в периоде: встречи и отсутствие. Фокус-время не учитывается,
+// его блоки фоновая задача переносит сама
+func (h *EventHandler) busySlots(userID uint, from, to time.Time) ([]models.TimeSlot, error) {
+	events, err := h.EventRepository.FindBusyIntervals(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var slots []models.TimeSlot
+	for _, ev := range events {
+		if ev.Focus {
+			continue
+		}
+		slots = append(slots, models.TimeSlot{Start: ev.StartDate, End: ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute)})
+	}
+	periods, err := h.OutOfOffice.FindByUser(userID, from)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range periods {
+		if period.StartDate.Before(to) {
+			slots = append(slots, models.TimeSlot{Start: period.StartDate, End: period.EndDate})
+		}
+	}
+	return slots, nil
+}
+
+// candidateSlots варианты встречи длительностью duration минут в периоде [from, to), которые
+// укладываются в рабочие часы будних дней по местному времени организатора
+func candidateSlots(from, to time.Time, duration int, location *time.Location) []models.TimeSlot {
+	length := time.Duration(duration) * time.Minute
+	var slots []models.TimeSlot
+	local := from.In(location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		dayEnd := time.Date(day.Year(), day.Month(), day.Day(), models.WorkdayEndHour, 0, 0, 0, location)
+		start := time.Date(day.Year(), day.Month(), day.Day(), models.WorkdayStartHour, 0, 0, 0, location)
+		for ; !start.Add(length).After(dayEnd); start = start.Add(slotStep) {
+			if start.Before(from) || start.Add(length).After(to) {
+				continue
+			}
+			slots = append(slots, models.TimeSlot{Start: start.UTC(), End: start.Add(length).UTC()})
+		}
+	}
+	return slots
+}
+
+// overlapsAny проверяет, пересекается ли интервал хотя бы с одним из занятых
+func overlapsAny(slot models.TimeSlot, busy []models.TimeSlot) bool {
+	for _, b := range busy {
+		if b.Start.Before(slot.End) && slot.Start.Before(b.End) {
+			return true
+		}
+	}
+	return false
+}
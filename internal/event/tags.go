@@ -0,0 +1,101 @@
+package event
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+)
+
+// SetTags заменяет метки события, доступно тем, кто может редактировать событие
+func (h *EventHandler) SetTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userId, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		eventId, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, err := request.HandelBody[TagsRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundEvent, err := h.EventRepository.FindInOrganization(eventId, orgID)
+		if err != nil {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
+		canManage, err := h.canManage(foundEvent, userId)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !canManage {
+			http.Error(w, "Only organizers can change event tags", http.StatusForbidden)
+			return
+		}
+		if ok := h.checkTags(w, body.TagIDs, userId, orgID); !ok {
+			return
+		}
+		if err := h.Tags.SetEventTags(foundEvent.ID, body.TagIDs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		tags, err := h.Tags.FindByIds(body.TagIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, tags, http.StatusOK)
+	}
+}
+
+// checkTags проверяет, что все метки существуют, не повторяются и доступны пользователю
+func (h *EventHandler) checkTags(w http.ResponseWriter, tagIDs []uint, userID, organizationID uint) bool {
+	seen := make(map[uint]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		if seen[id] {
+			http.Error(w, "Duplicate tag", http.StatusBadRequest)
+			return false
+		}
+		seen[id] = true
+	}
+	tags, err := h.Tags.FindByIds(tagIDs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if len(tags) != len(tagIDs) {
+		http.Error(w, "Tag not found", http.StatusBadRequest)
+		return false
+	}
+	for i := range tags {
+		if !tags[i].UsableBy(userID, organizationID) {
+			http.Error(w, "Tag is not available", http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// parseTagFilter разбирает необязательный фильтр списка событий по метке, 0 - без фильтра
+func parseTagFilter(r *http.Request) (uint, error) {
+	tagParam := r.URL.Query().Get("tag")
+	if tagParam == "" {
+		return 0, nil
+	}
+	tagID, err := strconv.ParseUint(tagParam, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid tag param")
+	}
+	return uint(tagID), nil
+}
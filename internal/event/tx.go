@@ -0,0 +1,39 @@
+package event
+
+import (
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventGuest"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventParticipant"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/tag"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+// begin открывает транзакцию для изменения события и возвращает копию обработчика,
+// репозитории которой пишут в эту транзакцию. Уведомления записываются в outbox
+// той же транзакцией: они уйдут, только если изменение сохранится.
+// Вызывающий делает defer tx.Rollback() и tx.Commit() перед ответом.
+func (h *EventHandler) begin() (*EventHandler, *gorm.DB, error) {
+	tx := h.EventRepository.DataBase.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	return h.withTx(&db.Db{DB: tx}), tx, nil
+}
+
+// withTx возвращает копию обработчика, репозитории и outbox которой пишут в уже открытую транзакцию
+func (h *EventHandler) withTx(txDB *db.Db) *EventHandler {
+	handler := *h
+	handler.EventRepository = NewEventRepository(txDB)
+	handler.EventParticipant = eventParticipant.NewEventParticipantRepository(txDB)
+	handler.Groups = group.NewGroupRepository(txDB)
+	handler.History = eventHistory.NewEventHistoryRepository(txDB)
+	handler.Tags = tag.NewTagRepository(txDB)
+	handler.Guests = eventGuest.NewEventGuestRepository(txDB)
+	if transactional, ok := h.Notifier.(notifier.Transactional); ok {
+		handler.Notifier = transactional.WithTx(txDB)
+	}
+	return &handler
+}
@@ -0,0 +1,32 @@
+package eventGuest
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToUserAlreadyParticipant(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "event_guests"\.".+ FROM "event_guests" JOIN events e ON e\.id = event_guests\.event_id .+ AND e\.organization_id = \$1 WHERE event_guests\.email = \$2`).
+		WithArgs(uint(2), "client@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "email"}).AddRow(3, 7, "client@example.com"))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "event_participants"`).
+		WithArgs(uint(7), uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(`UPDATE "event_guests" SET "deleted_at"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewEventGuestRepository(&db.Db{DB: gormDB})
+	eventIDs, err := repo.ConvertToUser(" Client@Example.com ", 5, 2)
+	require.NoError(t, err)
+	require.Empty(t, eventIDs)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
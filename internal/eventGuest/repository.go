@@ -0,0 +1,124 @@
+package eventGuest
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type EventGuestRepository struct {
+	DataBase *db.Db
+}
+
+// NewEventGuestRepository создает новый репозиторий гостей событий
+func NewEventGuestRepository(dataBase *db.Db) *EventGuestRepository {
+	return &EventGuestRepository{DataBase: dataBase}
+}
+
+// NormalizeEmail приводит email к виду, в котором он хранится у гостей
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Create сохраняет приглашение гостя
+func (repo *EventGuestRepository) Create(guest *models.EventGuest) (*models.EventGuest, error) {
+	guest.Email = NormalizeEmail(guest.Email)
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(guest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return guest, nil
+}
+
+// FindById находит приглашение гостя вместе с событием. Приглашения на удаленные события не находятся.
+func (repo *EventGuestRepository) FindById(id uint) (*models.EventGuest, error) {
+	var guest models.EventGuest
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Joins("JOIN events ON events.id = event_guests.event_id AND events.deleted_at IS NULL").
+		Preload("Event").
+		First(&guest, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &guest, nil
+}
+
+// FindByEvent возвращает гостей события
+func (repo *EventGuestRepository) FindByEvent(eventID uint) ([]models.EventGuest, error) {
+	var guests []models.EventGuest
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("event_id = ?", eventID).
+		Order("id").
+		Find(&guests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return guests, nil
+}
+
+// Respond сохраняет ответ гостя на приглашение
+func (repo *EventGuestRepository) Respond(id uint, status models.EventStatus, at time.Time) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventGuest{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": status, "responded_at": at}).Error
+}
+
+// ResetResponses сбрасывает ответы гостей после переноса события
+func (repo *EventGuestRepository) ResetResponses(eventID uint) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventGuest{}).
+		Where("event_id = ?", eventID).
+		Updates(map[string]any{"status": models.StatusSent, "responded_at": nil}).Error
+}
+
+// ConvertToUser переносит гостевые приглашения с этим email на события организации в участие пользователя.
+// Приглашения на события других организаций остаются гостевыми. Если пользователь уже участвует
+// в событии, приглашение гостя просто удаляется. Возвращает ID событий, в которые пользователь добавлен.
+func (repo *EventGuestRepository) ConvertToUser(email string, userID, organizationID uint) ([]uint, error) {
+	var eventIDs []uint
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var guests []models.EventGuest
+		err := tx.Joins("JOIN events e ON e.id = event_guests.event_id AND e.deleted_at IS NULL AND e.organization_id = ?", organizationID).
+			Where("event_guests.email = ?", NormalizeEmail(email)).
+			Find(&guests).Error
+		if err != nil {
+			return err
+		}
+		for _, guest := range guests {
+			var count int64
+			err := tx.Model(&models.EventParticipant{}).
+				Where("event_id = ? AND user_id = ?", guest.EventID, userID).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				participant := models.NewEventParticipant(guest.EventID, userID)
+				participant.Role = guest.Role
+				participant.Status = guest.Status
+				participant.RespondedAt = guest.RespondedAt
+				if err := tx.Create(participant).Error; err != nil {
+					return err
+				}
+				eventIDs = append(eventIDs, guest.EventID)
+			}
+			if err := tx.Delete(&models.EventGuest{}, guest.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eventIDs, nil
+}
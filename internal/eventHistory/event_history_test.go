@@ -0,0 +1,67 @@
+package eventHistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEvents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	before := &models.Event{Title: "Планерка", StartDate: start, Duration: 30}
+	after := &models.Event{Title: "Планерка", StartDate: start.Add(time.Hour), Duration: 60}
+
+	changes := models.DiffEvents(before, after)
+	require.Len(t, changes, 2)
+	require.Equal(t, 30, changes["duration"].From)
+	require.Equal(t, 60, changes["duration"].To)
+	require.Contains(t, changes, "start_date")
+	require.Empty(t, models.DiffEvents(before, before))
+}
+
+func TestFieldChangesRoundTrip(t *testing.T) {
+	changes := models.FieldChanges{
+		"title": {From: "Старое", To: "Новое"},
+	}
+	value, err := changes.Value()
+	require.NoError(t, err)
+
+	var scanned models.FieldChanges
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, "Старое", scanned["title"].From)
+	require.Equal(t, "Новое", scanned["title"].To)
+}
+
+func TestRecordChangeOnBehalf(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "id" FROM "events" WHERE .* FOR UPDATE`).
+		WithArgs(uint(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM "event_revisions"`).
+		WithArgs(uint(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	mock.ExpectQuery(`INSERT INTO "event_revisions"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, uint(5), 3, models.RevisionUpdated, uint(7), uint(1), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	ctx := context.WithValue(context.Background(), middleware.ContextUserIDKey, uint(1))
+	ctx = context.WithValue(ctx, middleware.ContextActorIDKey, uint(7))
+
+	repo := NewEventHistoryRepository(&db.Db{DB: gormDB})
+	err := repo.RecordChange(ctx, 5, models.RevisionUpdated, models.FieldChanges{
+		"title": {From: "Старое", To: "Новое"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,73 @@
+package eventHistory
+
+import (
+	"context"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventHistoryRepository struct {
+	DataBase *db.Db
+}
+
+// NewEventHistoryRepository создает новый репозиторий истории событий
+func NewEventHistoryRepository(dataBase *db.Db) *EventHistoryRepository {
+	return &EventHistoryRepository{DataBase: dataBase}
+}
+
+// Record сохраняет следующую версию события. Строка события блокируется на время записи,
+// чтобы параллельные изменения получили разные номера версий.
+func (repo *EventHistoryRepository) Record(revision *models.EventRevision) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Event
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&locked, revision.EventID).Error; err != nil {
+			return err
+		}
+		var version int
+		if err := tx.Model(&models.EventRevision{}).
+			Where("event_id = ?", revision.EventID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&version).Error; err != nil {
+			return err
+		}
+		revision.Version = version + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// FindByEvent возвращает историю события от первой версии к последней
+func (repo *EventHistoryRepository) FindByEvent(eventID uint) ([]models.EventRevision, error) {
+	var revisions []models.EventRevision
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("event_id = ?", eventID).
+		Order("version").
+		Find(&revisions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return revisions, nil
+}
+
+// RecordChange сохраняет изменение события от имени пользователя из контекста запроса.
+// При делегировании автором считается помощник, а владелец календаря попадает в OnBehalfOfID.
+func (repo *EventHistoryRepository) RecordChange(ctx context.Context, eventID uint, action models.RevisionAction, changes models.FieldChanges) error {
+	revision := &models.EventRevision{
+		EventID: eventID,
+		Action:  action,
+		ActorID: middleware.ActorID(ctx),
+		Changes: changes,
+	}
+	if userID, ok := ctx.Value(middleware.ContextUserIDKey).(uint); ok && userID != revision.ActorID {
+		revision.OnBehalfOfID = &userID
+	}
+	return repo.Record(revision)
+}
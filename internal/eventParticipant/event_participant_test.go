@@ -3,6 +3,7 @@ package eventParticipant
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,8 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendarShare"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
@@ -18,6 +21,7 @@ import (
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestAddParticipant(t *testing.T) {
@@ -83,8 +87,8 @@ func TestGetUserEvents(t *testing.T) {
 	require.NoError(t, err)
 
 	// Мокаем запрос на получение событий пользователя
-	mock.ExpectQuery(`SELECT events.* FROM "events" JOIN event_participants ON events.id = event_participants.event_id WHERE event_participants.user_id = $1`).
-		WithArgs(uint(1)).
+	mock.ExpectQuery(`SELECT .* FROM "events" JOIN event_participants ON events.id = event_participants.event_id AND event_participants.deleted_at IS NULL WHERE \(event_participants.user_id = \$1 AND events.organization_id = \$2\) AND "events"."deleted_at" IS NULL`).
+		WithArgs(uint(1), uint(3)).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "created_at", "updated_at", "deleted_at", "title", "description", "event_date", "creator_id",
 		}).AddRow(1, fixedTime, fixedTime, nil, "testevent", "description", date, 1))
@@ -92,7 +96,7 @@ func TestGetUserEvents(t *testing.T) {
 	dbWrapper := &db.Db{DB: gormDB}
 	repo := NewEventParticipantRepository(dbWrapper)
 	repo.DataBase.DB = repo.DataBase.DB.Model(&models.EventParticipant{}) // Установка модели таблицы
-	events, err := repo.GetUserEvents(uint(1))
+	events, err := repo.GetUserEvents(uint(1), uint(3))
 	require.NoError(t, err, "Get user events failed")
 	require.Len(t, events, 1)
 	require.NoError(t, mock.ExpectationsWereMet())
@@ -142,13 +146,28 @@ func TestDeleteEventParticipant(t *testing.T) {
 		WithArgs(testEventID, testUserID).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
+	// Удаление участника, версия события и история сохраняются одной транзакцией
 	mock.ExpectBegin()
 
 	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "event_participants" SET "deleted_at"=$1 WHERE (event_id = $2 AND user_id = $3) AND "event_participants"."deleted_at" IS NULL`)).
 		WithArgs(sqlmock.AnyArg(), testEventID, testParticipantID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "version"=version + 1,"updated_at"=$1 WHERE id = $2`)).
+		WithArgs(sqlmock.AnyArg(), testEventID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "events" WHERE .* FOR UPDATE`).
+		WithArgs(testEventID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testEventID))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM "event_revisions"`).
+		WithArgs(testEventID).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "event_revisions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 	mock.ExpectCommit()
+
+	history := eventHistory.NewEventHistoryRepository(&db.Db{DB: gormDB})
 	dbWrapper := &db.Db{DB: gormDB}
 	repo := NewEventParticipantRepository(dbWrapper)
 	repo.DataBase.DB = repo.DataBase.DB.Model(&models.EventParticipant{})
@@ -160,6 +179,7 @@ func TestDeleteEventParticipant(t *testing.T) {
 	handler := &EventParticipantHandler{
 		EventParticipantRepository: repo,
 		JWTService:                 newJWT,
+		History:                    history,
 	}
 
 	// Выполнение запроса
@@ -179,3 +199,162 @@ func TestDeleteEventParticipant(t *testing.T) {
 	require.Equal(t, w.Code, 200)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetUserEventsForbidsOtherUsers(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	//у пользователя 2 нет доступа к календарю пользователя 1
+	mock.ExpectQuery(`SELECT \* FROM "calendar_shares"`).
+		WithArgs(uint(2), uint(2), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "grantee_user_id", "level"}))
+
+	dbWrapper := &db.Db{DB: gormDB}
+	handler := &EventParticipantHandler{
+		EventParticipantRepository: NewEventParticipantRepository(dbWrapper),
+		CalendarShares:             calendarShare.NewCalendarShareRepository(dbWrapper),
+	}
+	r := chi.NewRouter()
+	r.Get("/event-participant/user/{user_id}/events", handler.GetUserEvents())
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/event-participant/user/1/events", nil)
+	ctx := context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(2))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(3))
+	r.ServeHTTP(w, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCanManageEvent(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	// Пользователь не создатель, но соорганизатор события
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE (id = $1 AND creator_id = $2) AND "events"."deleted_at" IS NULL`)).
+		WithArgs(uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "event_participants" WHERE (event_id = $1 AND user_id = $2 AND role IN ($3,$4)) AND "event_participants"."deleted_at" IS NULL`)).
+		WithArgs(uint(1), uint(2), models.RoleOrganizer, models.RoleCoOrganizer).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	dbWrapper := &db.Db{DB: gormDB}
+	repo := NewEventParticipantRepository(dbWrapper)
+	canManage, err := repo.CanManageEvent(uint(1), uint(2))
+	require.NoError(t, err, "Check organizer failed")
+	require.True(t, canManage, "Co-organizer should manage event")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStatus(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "event_participants" SET "responded_at"=$1,"status"=$2,"status_message"=$3,"updated_at"=$4 WHERE (event_id = $5 AND user_id = $6) AND "event_participants"."deleted_at" IS NULL`)).
+		WithArgs(nil, models.StatusDecline, "", sqlmock.AnyArg(), uint(3), uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := NewEventParticipantRepository(&db.Db{DB: gormDB})
+	require.NoError(t, repo.UpdateStatus(3, 7, models.StatusDecline, ""))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckInNotParticipant(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	now := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "event_participants" SET "attended"=$1,"checked_in_at"=$2,"updated_at"=$3 WHERE (event_id = $4 AND user_id = $5) AND "event_participants"."deleted_at" IS NULL`)).
+		WithArgs(true, now, sqlmock.AnyArg(), uint(3), uint(7)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewEventParticipantRepository(&db.Db{DB: gormDB})
+	require.ErrorIs(t, repo.CheckIn(3, 7, now), gorm.ErrRecordNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAttendanceSummaries(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	// незакончившиеся события в сводку не попадают
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ep.event_id, COUNT(*) FILTER (WHERE ep.status = $1) AS accepted, `+
+		`COUNT(*) FILTER (WHERE ep.status = $2 AND ep.attended IS TRUE) AS attended, `+
+		`COUNT(*) FILTER (WHERE ep.status = $3 AND ep.attended IS NOT TRUE) AS no_shows `+
+		`FROM event_participants ep JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL `+
+		`WHERE (ep.event_id IN ($4,$5) AND ep.deleted_at IS NULL) AND e.start_date + (e.duration || ' minutes')::interval <= $6 `+
+		`GROUP BY "ep"."event_id" ORDER BY ep.event_id`)).
+		WithArgs(models.StatusAccepted, models.StatusAccepted, models.StatusAccepted, uint(1), uint(2), now).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "accepted", "attended", "no_shows"}).
+			AddRow(1, 4, 3, 1).
+			AddRow(2, 0, 0, 0))
+
+	repo := NewEventParticipantRepository(&db.Db{DB: gormDB})
+	summaries, err := repo.AttendanceSummaries([]uint{1, 2}, now)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	require.Equal(t, 1, summaries[0].NoShows)
+	require.InDelta(t, 0.75, summaries[0].Rate(), 0.001)
+	require.Zero(t, summaries[1].Rate())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type failingInviter struct{ calls int }
+
+func (f *failingInviter) SendInvitation(context.Context, *db.Db, uint, uint) error {
+	f.calls++
+	return errors.New("outbox unavailable")
+}
+
+func TestAddEventParticipantRollsBackWithoutInvitation(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE (id = $1 AND creator_id = $2)`)).
+		WithArgs(uint(33), uint(42)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "users" JOIN events ON events.organization_id = users.organization_id`)).
+		WithArgs(uint(33), uint(54)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	// участник, версия, история и приглашение сохраняются одной транзакцией
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "event_participants"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "version"=version + 1,"updated_at"=$1 WHERE id = $2`)).
+		WithArgs(sqlmock.AnyArg(), uint(33)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "id" FROM "events" WHERE .* FOR UPDATE`).
+		WithArgs(uint(33), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(33))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM "event_revisions"`).
+		WithArgs(uint(33)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0))
+	mock.ExpectQuery(`INSERT INTO "event_revisions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	// приглашение не записалось, участник не сохраняется
+	mock.ExpectRollback()
+
+	dbWrapper := &db.Db{DB: gormDB}
+	invites := &failingInviter{}
+	handler := &EventParticipantHandler{
+		EventParticipantRepository: NewEventParticipantRepository(dbWrapper),
+		History:                    eventHistory.NewEventHistoryRepository(dbWrapper),
+		Invites:                    invites,
+	}
+	r := chi.NewRouter()
+	r.Post("/event-participant/", handler.AddEventParticipant())
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/event-participant/", bytes.NewReader([]byte(`{"event_id":33,"user_id":54}`)))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextUserIDKey, uint(42)))
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Equal(t, 1, invites.calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
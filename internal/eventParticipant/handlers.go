@@ -1,57 +1,89 @@
 package eventParticipant
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendarShare"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/event"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
+// EventInviter отправляет приглашение добавленному участнику внутри транзакции tx
+type EventInviter interface {
+	SendInvitation(ctx context.Context, tx *db.Db, eventID, userID uint) error
+}
+
 type EventParticipantHandler struct {
 	EventParticipantRepository *EventParticipantRepository
 	JWTService                 *jwt.JWT
+	Delegations                *delegation.DelegationRepository
+	History                    *eventHistory.EventHistoryRepository
+	CalendarShares             *calendarShare.CalendarShareRepository
+	Invites                    EventInviter
 }
 
 type EventParticipantDepsHandler struct {
 	EventParticipantRepository *EventParticipantRepository
 	JWTService                 *jwt.JWT
+	Delegations                *delegation.DelegationRepository
+	History                    *eventHistory.EventHistoryRepository
+	CalendarShares             *calendarShare.CalendarShareRepository
+	Invites                    EventInviter
 }
 
 func NewEventParticipantHandler(mux *chi.Mux, deps EventParticipantDepsHandler) {
 	handler := &EventParticipantHandler{
 		EventParticipantRepository: deps.EventParticipantRepository,
 		JWTService:                 deps.JWTService,
+		Delegations:                deps.Delegations,
+		History:                    deps.History,
+		CalendarShares:             deps.CalendarShares,
+		Invites:                    deps.Invites,
 	}
 	mux.Handle("POST /event-participant/",
-		middleware.IsAuthed(handler.AddEventParticipant(), deps.JWTService))
+		middleware.IsAuthedAs(handler.AddEventParticipant(), deps.JWTService, deps.Delegations))
 	mux.Handle("DELETE /event-participant/{id}/event/{event_id}",
-		middleware.IsAuthed(handler.DeleteEventParticipant(), deps.JWTService))
+		middleware.IsAuthedAs(handler.DeleteEventParticipant(), deps.JWTService, deps.Delegations))
 	mux.Handle("GET /event-participant/{id}",
-		middleware.IsAuthed(handler.GetEventParticipantById(), deps.JWTService))
+		middleware.IsAuthedAs(handler.GetEventParticipantById(), deps.JWTService, deps.Delegations))
 	mux.Handle("GET /event-participant/user/{user_id}/events",
-		middleware.IsAuthed(handler.GetUserEvents(), deps.JWTService))
+		middleware.IsAuthedAs(handler.GetUserEvents(), deps.JWTService, deps.Delegations))
 	mux.Handle("POST /event-participant/is-participant",
-		middleware.IsAuthed(handler.IsParticipant(), deps.JWTService))
+		middleware.IsAuthedAs(handler.IsParticipant(), deps.JWTService, deps.Delegations))
 }
 
 // AddEventParticipant Добавляет нового участника в событие
 func (h *EventParticipantHandler) AddEventParticipant() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
-			EventID uint `json:"event_id"`
-			UserID  uint `json:"user_id"`
+			EventID uint                   `json:"event_id"`
+			UserID  uint                   `json:"user_id"`
+			Role    models.ParticipantRole `json:"role"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
 			return
 		}
+		if req.Role == "" {
+			req.Role = models.RoleRequired
+		}
+		if !req.Role.IsValidInviteRole() {
+			http.Error(w, "Invalid participant role", http.StatusBadRequest)
+			return
+		}
 		userID, err := event.GetUserIDFromContext(r.Context())
 		if err != nil {
 			switch err {
@@ -70,20 +102,58 @@ func (h *EventParticipantHandler) AddEventParticipant() http.HandlerFunc {
 			"AddEvent: ctxUserID=%d, req.EventID=%d, req.UserID=%d",
 			userID, req.EventID, req.UserID,
 		)
-		isCreator, err := h.EventParticipantRepository.IsEventCreatorById(req.EventID, userID)
+		canManage, err := h.EventParticipantRepository.CanManageEvent(req.EventID, userID)
 		if err != nil {
-			http.Error(w, "Error checking if user is creator of event", http.StatusInternalServerError)
+			http.Error(w, "Error checking if user is organizer of event", http.StatusInternalServerError)
 			return
 		}
-		if !isCreator {
-			http.Error(w, "User is not creator of event", http.StatusForbidden)
+		if !canManage {
+			http.Error(w, "User is not organizer of event", http.StatusForbidden)
+			return
+		}
+		//участником может стать только пользователь из организации события
+		sameOrg, err := h.EventParticipantRepository.InEventOrganization(req.EventID, req.UserID)
+		if err != nil {
+			http.Error(w, "Error checking user organization", http.StatusInternalServerError)
+			return
+		}
+		if !sameOrg {
+			http.Error(w, "User not found", http.StatusNotFound)
 			return
 		}
 
-		if err := h.EventParticipantRepository.AddParticipant(req.EventID, req.UserID); err != nil {
+		//участник, версия события и история сохраняются одной транзакцией, дальше h работает в ней
+		h, tx, err := h.begin()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+		if err := h.EventParticipantRepository.AddParticipantWithRole(req.EventID, req.UserID, req.Role, models.StatusAccepted); err != nil {
 			http.Error(w, "Failed to add participant", http.StatusInternalServerError)
 			return
 		}
+		if err := h.EventParticipantRepository.BumpEventVersion(req.EventID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = h.History.RecordChange(r.Context(), req.EventID, models.RevisionParticipantAdded, models.FieldChanges{
+			models.ParticipantField(req.UserID, "role"):   {To: req.Role},
+			models.ParticipantField(req.UserID, "status"): {To: models.StatusAccepted},
+		})
+		if err != nil {
+			http.Error(w, "Failed to record event history", http.StatusInternalServerError)
+			return
+		}
+		//приглашение уходит через outbox той же транзакции
+		if err := h.Invites.SendInvitation(r.Context(), &db.Db{DB: tx}, req.EventID, req.UserID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		w.WriteHeader(http.StatusCreated)
 	}
@@ -120,26 +190,49 @@ func (h *EventParticipantHandler) DeleteEventParticipant() http.HandlerFunc {
 			http.Error(w, "Error getting user ID from context", http.StatusInternalServerError)
 			return
 		}
-		isCreator, err := h.EventParticipantRepository.IsEventCreatorById(uint(eventID), userID)
+		canManage, err := h.EventParticipantRepository.CanManageEvent(uint(eventID), userID)
 		if err != nil {
-			http.Error(w, "Error checking if user is creator of event", http.StatusInternalServerError)
+			http.Error(w, "Error checking if user is organizer of event", http.StatusInternalServerError)
 			return
 		}
-		if !isCreator {
-			http.Error(w, "User is not creator of event", http.StatusForbidden)
+		if !canManage {
+			http.Error(w, "User is not organizer of event", http.StatusForbidden)
 			return
 		}
 
+		//удаление, версия события и история сохраняются одной транзакцией, дальше h работает в ней
+		h, tx, err := h.begin()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
 		if err := h.EventParticipantRepository.RemoveParticipant(uint(eventID), uint(participantID)); err != nil {
 			http.Error(w, "Failed to remove participant", http.StatusInternalServerError)
 			return
 		}
+		if err := h.EventParticipantRepository.BumpEventVersion(uint(eventID)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = h.History.RecordChange(r.Context(), uint(eventID), models.RevisionParticipantRemoved, models.FieldChanges{
+			models.ParticipantField(uint(participantID), "removed"): {From: false, To: true},
+		})
+		if err != nil {
+			http.Error(w, "Failed to record event history", http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
-// GetEventParticipantById Возвращает список участников события с приглашениями
+// GetEventParticipantById Возвращает список участников события с приглашениями.
+// Доступно только участникам события из той же организации.
 func (h *EventParticipantHandler) GetEventParticipantById() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idParam := chi.URLParam(r, "id")
@@ -148,8 +241,21 @@ func (h *EventParticipantHandler) GetEventParticipantById() http.HandlerFunc {
 			http.Error(w, "Invalid ID", http.StatusBadRequest)
 			return
 		}
+		callerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		if ok := h.checkEventAccess(w, uint(id), callerID, orgID); !ok {
+			return
+		}
 
-		participants, err := h.EventParticipantRepository.GetUsersWithInvites(uint(id))
+		participants, err := h.EventParticipantRepository.GetUsersWithInvites(uint(id), orgID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Event not found", http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			http.Error(w, "Failed to get participants", http.StatusInternalServerError)
 			return
@@ -164,7 +270,8 @@ func (h *EventParticipantHandler) GetEventParticipantById() http.HandlerFunc {
 	}
 }
 
-// GetUserEvents Возвращает список событий, в которых участвует пользователь
+// GetUserEvents Возвращает список событий организации, в которых участвует пользователь.
+// Чужой список доступен только при доступе к календарю пользователя с подробностями.
 func (h *EventParticipantHandler) GetUserEvents() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userIDParam := chi.URLParam(r, "user_id")
@@ -173,8 +280,26 @@ func (h *EventParticipantHandler) GetUserEvents() http.HandlerFunc {
 			http.Error(w, "Invalid user ID", http.StatusBadRequest)
 			return
 		}
+		//при делегировании в контексте уже находится владелец календаря
+		callerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if uint(userID) != callerID {
+			level, err := h.CalendarShares.LevelFor(uint(userID), callerID)
+			if err != nil {
+				http.Error(w, "Failed to check calendar access", http.StatusInternalServerError)
+				return
+			}
+			if !level.Allows(models.ShareDetails) {
+				http.Error(w, "You do not have access to this user's events", http.StatusForbidden)
+				return
+			}
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
 
-		events, err := h.EventParticipantRepository.GetUserEvents(uint(userID))
+		events, err := h.EventParticipantRepository.GetUserEvents(uint(userID), orgID)
 		if err != nil {
 			http.Error(w, "Failed to get user events", http.StatusInternalServerError)
 			return
@@ -187,7 +312,8 @@ func (h *EventParticipantHandler) GetUserEvents() http.HandlerFunc {
 	}
 }
 
-// IsParticipant Проверяет, является ли пользователь участником события
+// IsParticipant Проверяет, является ли пользователь участником события.
+// Спрашивать могут только участники события из той же организации.
 func (h *EventParticipantHandler) IsParticipant() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
@@ -198,8 +324,17 @@ func (h *EventParticipantHandler) IsParticipant() http.HandlerFunc {
 			http.Error(w, "Invalid request body", http.StatusBadRequest)
 			return
 		}
+		callerID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		if ok := h.checkEventAccess(w, req.EventID, callerID, orgID); !ok {
+			return
+		}
 
-		isParticipant, err := h.EventParticipantRepository.IsParticipant(req.EventID, req.UserID)
+		isParticipant, err := h.EventParticipantRepository.IsParticipantInOrganization(req.EventID, req.UserID, orgID)
 		if err != nil {
 			http.Error(w, "Failed to check participation", http.StatusInternalServerError)
 			return
@@ -212,3 +347,18 @@ func (h *EventParticipantHandler) IsParticipant() http.HandlerFunc {
 		}
 	}
 }
+
+// checkEventAccess пропускает только участников события из организации пользователя,
+// создатель события всегда его участник. Для чужих событий отвечает 404, не раскрывая их наличие.
+func (h *EventParticipantHandler) checkEventAccess(w http.ResponseWriter, eventID, userID, organizationID uint) bool {
+	isParticipant, err := h.EventParticipantRepository.IsParticipantInOrganization(eventID, userID, organizationID)
+	if err != nil {
+		http.Error(w, "Failed to check participation", http.StatusInternalServerError)
+		return false
+	}
+	if !isParticipant {
+		http.Error(w, "Event not found", http.StatusNotFound)
+		return false
+	}
+	return true
+}
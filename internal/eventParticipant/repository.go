@@ -1,6 +1,8 @@
 package eventParticipant
 
 import (
+	"time"
+
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
 	"gorm.io/gorm"
@@ -26,6 +28,27 @@ func (repo *EventParticipantRepository) AddParticipant(eventID, userID uint) err
 	return nil
 }
 
+// Add сохраняет подготовленную запись участника события
+func (repo *EventParticipantRepository) Add(participant *models.EventParticipant) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Create(participant).Error
+}
+
+// AddParticipantWithRole добавляет пользователя к событию с указанной ролью и статусом
+func (repo *EventParticipantRepository) AddParticipantWithRole(eventID, userID uint, role models.ParticipantRole, status models.EventStatus) error {
+	db := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{})
+	participant := models.NewEventParticipant(eventID, userID)
+	participant.Role = role
+	participant.Status = status
+	if err := db.Create(participant).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 // AddParticipant обновляет статус пользователя
 func (repo *EventParticipantRepository) UpdateParticipant(eventPart *models.EventParticipant) (*models.EventParticipant, error) {
 	result := repo.DataBase.DB.Save(eventPart)
@@ -35,6 +58,127 @@ func (repo *EventParticipantRepository) UpdateParticipant(eventPart *models.Even
 	return eventPart, nil
 }
 
+// UpdateStatus меняет статус существующего участника события, прежний ответ участника сбрасывается
+func (repo *EventParticipantRepository) UpdateStatus(eventID, userID uint, status models.EventStatus, message string) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Updates(map[string]any{"status": status, "status_message": message, "responded_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// Respond сохраняет ответ участника на приглашение и время ответа
+func (repo *EventParticipantRepository) Respond(eventID, userID uint, status models.EventStatus) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Updates(map[string]any{"status": status, "status_message": "", "responded_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// CheckIn отмечает присутствие участника по его собственной отметке
+func (repo *EventParticipantRepository) CheckIn(eventID, userID uint, at time.Time) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Updates(map[string]any{"attended": true, "checked_in_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAttendance сохраняет отметку организатора о присутствии участника
+func (repo *EventParticipantRepository) MarkAttendance(eventID, userID uint, attended bool) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Update("attended", attended)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// EventAttendance возвращает участников события с их присутствием
+func (repo *EventParticipantRepository) EventAttendance(eventID uint) ([]models.ParticipantAttendance, error) {
+	var attendance []models.ParticipantAttendance
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("ep.user_id, u.username AS user_name, ep.status, ep.attended, ep.checked_in_at").
+		Joins("JOIN users u ON u.id = ep.user_id").
+		Where("ep.event_id = ? AND ep.deleted_at IS NULL", eventID).
+		Order("u.username").
+		Scan(&attendance).Error
+	if err != nil {
+		return nil, err
+	}
+	return attendance, nil
+}
+
+// AttendanceSummaries считает присутствие одним запросом по каждому событию, закончившемуся до before
+func (repo *EventParticipantRepository) AttendanceSummaries(eventIDs []uint, before time.Time) ([]models.AttendanceSummary, error) {
+	var summaries []models.AttendanceSummary
+	if len(eventIDs) == 0 {
+		return summaries, nil
+	}
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("ep.event_id, "+
+			"COUNT(*) FILTER (WHERE ep.status = ?) AS accepted, "+
+			"COUNT(*) FILTER (WHERE ep.status = ? AND ep.attended IS TRUE) AS attended, "+
+			"COUNT(*) FILTER (WHERE ep.status = ? AND ep.attended IS NOT TRUE) AS no_shows",
+			models.StatusAccepted, models.StatusAccepted, models.StatusAccepted).
+		Joins("JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL").
+		Where("ep.event_id IN ? AND ep.deleted_at IS NULL", eventIDs).
+		Where("e.start_date + (e.duration || ' minutes')::interval <= ?", before).
+		Group("ep.event_id").
+		Order("ep.event_id").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// UserAttendance считает присутствие пользователя на принятых встречах, закончившихся до before
+func (repo *EventParticipantRepository) UserAttendance(userID uint, before time.Time) (*models.UserAttendance, error) {
+	var attendance models.UserAttendance
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("COUNT(*) AS accepted, "+
+			"COUNT(*) FILTER (WHERE ep.attended IS TRUE) AS attended, "+
+			"COUNT(*) FILTER (WHERE ep.attended IS NOT TRUE) AS no_shows").
+		Joins("JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL").
+		Where("ep.user_id = ? AND ep.status = ? AND ep.deleted_at IS NULL", userID, models.StatusAccepted).
+		Where("e.start_date + (e.duration || ' minutes')::interval <= ?", before).
+		Scan(&attendance).Error
+	if err != nil {
+		return nil, err
+	}
+	attendance.UserID = userID
+	return &attendance, nil
+}
+
 // RemoveParticipant удаляет пользователя из события
 func (repo *EventParticipantRepository) RemoveParticipant(eventID, userID uint) error {
 	db := repo.DataBase.DB.
@@ -54,7 +198,7 @@ func (repo *EventParticipantRepository) GetEventParticipants(eventID uint) ([]mo
 		Table("event_participants").
 		Joins("JOIN users ON users.id = event_participants.user_id").
 		Where("event_participants.event_id = ? AND event_participants.deleted_at IS NULL AND users.deleted_at IS NULL", eventID).
-		Select("users.id, users.username, users.email"). // Выбираем только нужные поля
+		Select("users.id, users.username, users.email, users.timezone, users.locale"). // Выбираем только нужные поля
 		Scan(&users).Error
 	if err != nil {
 		return nil, err
@@ -62,15 +206,14 @@ func (repo *EventParticipantRepository) GetEventParticipants(eventID uint) ([]mo
 	return users, nil
 }
 
-// GetUserEvents возвращает список событий, в которых участвует пользователь
-func (repo *EventParticipantRepository) GetUserEvents(userID uint) ([]models.Event, error) {
-	db := repo.DataBase.DB.
-		Session(&gorm.Session{NewDB: true}).
-		Model(&models.EventParticipant{})
+// GetUserEvents возвращает события организации, в которых участвует пользователь
+func (repo *EventParticipantRepository) GetUserEvents(userID, organizationID uint) ([]models.Event, error) {
 	var events []models.Event
-	err := db.Table("events").
-		Joins("JOIN event_participants ON events.id = event_participants.event_id").
-		Where("event_participants.user_id = ?", userID).
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("events").
+		Joins("JOIN event_participants ON events.id = event_participants.event_id AND event_participants.deleted_at IS NULL").
+		Where("event_participants.user_id = ? AND events.organization_id = ?", userID, organizationID).
 		Find(&events).Error
 	if err != nil {
 		return nil, err
@@ -93,6 +236,47 @@ func (repo *EventParticipantRepository) IsParticipant(eventID, userID uint) (boo
 	return count > 0, nil
 }
 
+// IsParticipantInOrganization проверяет участие пользователя в событии указанной организации
+func (repo *EventParticipantRepository) IsParticipantInOrganization(eventID, userID, organizationID uint) (bool, error) {
+	var count int64
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Joins("JOIN events ON events.id = event_participants.event_id AND events.deleted_at IS NULL").
+		Where("event_participants.event_id = ? AND event_participants.user_id = ? AND events.organization_id = ?", eventID, userID, organizationID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindParticipations возвращает участие указанных пользователей в указанных событиях
+func (repo *EventParticipantRepository) FindParticipations(eventIDs, userIDs []uint) ([]models.EventParticipant, error) {
+	var participations []models.EventParticipant
+	if len(eventIDs) == 0 || len(userIDs) == 0 {
+		return participations, nil
+	}
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("event_id IN ? AND user_id IN ?", eventIDs, userIDs).
+		Find(&participations).Error
+	if err != nil {
+		return nil, err
+	}
+	return participations, nil
+}
+
+// BumpEventVersion увеличивает версию события после изменения состава участников,
+// чтобы клиенты с устаревшей копией получили конфликт версий
+func (repo *EventParticipantRepository) BumpEventVersion(eventID uint) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Where("id = ?", eventID).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
 // IsEventCreatorById проверяет, является ли пользователь создателем события
 func (repo *EventParticipantRepository) IsEventCreatorById(eventID, userID uint) (bool, error) {
 	db := repo.DataBase.DB.
@@ -108,12 +292,54 @@ func (repo *EventParticipantRepository) IsEventCreatorById(eventID, userID uint)
 	return count > 0, nil
 }
 
-// получаем пользователей с приглашениями по событию
-func (repo EventParticipantRepository) GetUsersWithInvites(eventID uint) (*models.EventParticipant, error) {
+// CanManageEvent проверяет, может ли пользователь редактировать событие и список участников:
+// это создатель события, а также организаторы и соорганизаторы
+func (repo *EventParticipantRepository) CanManageEvent(eventID, userID uint) (bool, error) {
+	isCreator, err := repo.IsEventCreatorById(eventID, userID)
+	if err != nil || isCreator {
+		return isCreator, err
+	}
+	db := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{})
+	var count int64
+	err = db.Where("event_id = ? AND user_id = ? AND role IN ?", eventID, userID,
+		[]models.ParticipantRole{models.RoleOrganizer, models.RoleCoOrganizer}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// InEventOrganization проверяет, что пользователь состоит в той же организации, что и событие
+func (repo *EventParticipantRepository) InEventOrganization(eventID, userID uint) (bool, error) {
+	var count int64
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("users").
+		Joins("JOIN events ON events.organization_id = users.organization_id AND events.deleted_at IS NULL").
+		Where("events.id = ? AND users.id = ? AND users.deleted_at IS NULL", eventID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// получаем пользователей с приглашениями по событию, только если событие принадлежит организации
+func (repo EventParticipantRepository) GetUsersWithInvites(eventID, organizationID uint) (*models.EventParticipant, error) {
 	var inviteUsers *models.EventParticipant
-	result := repo.DataBase.DB.Where("event_id = ?", eventID).Find(&inviteUsers)
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Joins("JOIN events ON events.id = event_participants.event_id AND events.deleted_at IS NULL").
+		Where("event_participants.event_id = ? AND events.organization_id = ?", eventID, organizationID).
+		Find(&inviteUsers)
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
 	return inviteUsers, nil
 }
@@ -0,0 +1,22 @@
+package eventParticipant
+
+import (
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+// begin открывает транзакцию для изменения состава участников и возвращает копию обработчика,
+// репозитории которой пишут в эту транзакцию. Вызывающий делает defer tx.Rollback()
+// и tx.Commit() перед ответом.
+func (h *EventParticipantHandler) begin() (*EventParticipantHandler, *gorm.DB, error) {
+	tx := h.EventParticipantRepository.DataBase.DB.Begin()
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	txDB := &db.Db{DB: tx}
+	handler := *h
+	handler.EventParticipantRepository = NewEventParticipantRepository(txDB)
+	handler.History = eventHistory.NewEventHistoryRepository(txDB)
+	return &handler, tx, nil
+}
@@ -0,0 +1,131 @@
+package feedback
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+func anyArgs(n int) []driver.Value {
+	args := make([]driver.Value, n)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	return args
+}
+
+func TestWorkerRunOnce(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	database := &db.Db{DB: gormDB}
+	outbox := notifier.NewOutbox(notifier.NewOutboxRepository(database), []string{"inapp"})
+	worker := NewWorker(NewFeedbackRepository(database), &configs.Config{}, outbox, nopLogger{})
+
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT ep.event_id, ep.user_id, u.email, u.locale, u.timezone, e.title, e.organization_id FROM event_participants ep .*NOT EXISTS`).
+		WithArgs(models.StatusAccepted, now.Add(-Lookback), now, batchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "user_id", "email", "title", "organization_id"}).
+			AddRow(1, 2, "a@example.com", "Планерка", 5).
+			AddRow(1, 3, "b@example.com", "Планерка", 5))
+	// первому участнику опрос фиксируется и записывается в outbox той же транзакцией
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "feedback_surveys" .* ON CONFLICT DO NOTHING`).
+		WithArgs(anyArgs(10)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`INSERT INTO "outbox_messages"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, uint(5), "inapp", sqlmock.AnyArg(), "a@example.com", sqlmock.AnyArg(), "pending", 0, sqlmock.AnyArg(), "", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	// второму опрос уже отправлен другим экземпляром
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "feedback_surveys" .* ON CONFLICT DO NOTHING`).
+		WithArgs(anyArgs(10)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	sent, err := worker.RunOnce(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, notifier.Notification) error {
+	return errors.New("outbox unavailable")
+}
+
+func TestWorkerRunOnceSendError(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectQuery(`FROM event_participants ep`).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "user_id", "email", "title"}).
+			AddRow(1, 2, "a@example.com", "Планерка"))
+	// опрос не сохраняется без уведомления и будет отправлен при следующей проверке
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "feedback_surveys"`).
+		WithArgs(anyArgs(10)...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectRollback()
+
+	worker := NewWorker(NewFeedbackRepository(&db.Db{DB: gormDB}), &configs.Config{}, failingNotifier{}, nopLogger{})
+	sent, err := worker.RunOnce(context.Background(), time.Now().UTC())
+	require.NoError(t, err)
+	require.Zero(t, sent)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSubmitWithoutSurvey(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "feedback_surveys" SET`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewFeedbackRepository(&db.Db{DB: gormDB})
+	err := repo.Submit(1, 2, 5, true, "", time.Now().UTC())
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSummaries(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectQuery(`SELECT event_id, COUNT\(\*\) AS sent, COUNT\(submitted_at\) AS responses.*GROUP BY "event_id"`).
+		WithArgs(uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "sent", "responses", "avg_rating", "needed_share"}).
+			AddRow(1, 4, 2, 4.5, 0.5).
+			AddRow(2, 3, 0, 0, 0))
+
+	repo := NewFeedbackRepository(&db.Db{DB: gormDB})
+	summaries, err := repo.Summaries([]uint{1, 2})
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	require.Equal(t, 2, summaries[0].Responses)
+	require.InDelta(t, 4.5, summaries[0].AvgRating, 0.001)
+
+	empty, err := repo.Summaries(nil)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
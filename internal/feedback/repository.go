@@ -0,0 +1,110 @@
+package feedback
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeedbackRepository struct {
+	DataBase *db.Db
+}
+
+// NewFeedbackRepository создает новый репозиторий опросов после встреч
+func NewFeedbackRepository(dataBase *db.Db) *FeedbackRepository {
+	return &FeedbackRepository{DataBase: dataBase}
+}
+
+// PendingRecipients возвращает участников, принявших приглашение на встречи, закончившиеся
+// в промежутке [since, until), которым опрос еще не отправлялся. Блоки фокус-времени пропускаются
+func (repo *FeedbackRepository) PendingRecipients(since, until time.Time, limit int) ([]models.SurveyRecipient, error) {
+	var recipients []models.SurveyRecipient
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("ep.event_id, ep.user_id, u.email, u.locale, u.timezone, e.title, e.organization_id").
+		Joins("JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL AND e.focus = false").
+		Joins("JOIN users u ON u.id = ep.user_id AND u.deleted_at IS NULL").
+		Where("ep.deleted_at IS NULL AND ep.status = ?", models.StatusAccepted).
+		Where("e.start_date + (e.duration || ' minutes')::interval >= ? AND e.start_date + (e.duration || ' minutes')::interval < ?", since, until).
+		Where("NOT EXISTS (SELECT 1 FROM feedback_surveys s WHERE s.event_id = ep.event_id AND s.user_id = ep.user_id)").
+		Order("ep.event_id, ep.user_id").
+		Limit(limit).
+		Scan(&recipients).Error
+	if err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+// CreateSent сохраняет отправленный опрос. Возвращает false, если опрос этому участнику
+// уже отправлен другим экземпляром обработчика.
+func (repo *FeedbackRepository) CreateSent(eventID, userID uint, sentAt time.Time) (bool, error) {
+	survey := &models.FeedbackSurvey{EventID: eventID, UserID: userID, SentAt: sentAt}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(survey)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Submit сохраняет ответ участника на отправленный ему опрос
+func (repo *FeedbackRepository) Submit(eventID, userID uint, rating int, needed bool, comment string, at time.Time) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.FeedbackSurvey{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Updates(map[string]any{"rating": rating, "needed": needed, "comment": comment, "submitted_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Summaries считает ответы по каждому событию одним запросом
+func (repo *FeedbackRepository) Summaries(eventIDs []uint) ([]models.FeedbackSummary, error) {
+	var summaries []models.FeedbackSummary
+	if len(eventIDs) == 0 {
+		return summaries, nil
+	}
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.FeedbackSurvey{}).
+		Select("event_id, COUNT(*) AS sent, COUNT(submitted_at) AS responses, "+
+			"COALESCE(AVG(rating), 0) AS avg_rating, "+
+			"COALESCE(AVG(CASE WHEN needed THEN 1.0 ELSE 0.0 END) FILTER (WHERE submitted_at IS NOT NULL), 0) AS needed_share").
+		Where("event_id IN ?", eventIDs).
+		Group("event_id").
+		Order("event_id").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// Comments возвращает ответы с комментариями по событиям, новые первыми
+func (repo *FeedbackRepository) Comments(eventIDs []uint) ([]models.FeedbackSurvey, error) {
+	var surveys []models.FeedbackSurvey
+	if len(eventIDs) == 0 {
+		return surveys, nil
+	}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("event_id IN ? AND submitted_at IS NOT NULL AND comment <> ''", eventIDs).
+		Order("submitted_at DESC").
+		Find(&surveys)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return surveys, nil
+}
@@ -0,0 +1,106 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultInterval период проверки закончившихся встреч
+	DefaultInterval = time.Minute
+	// Lookback встречи, закончившиеся раньше, опросом не охватываются
+	Lookback  = 7 * 24 * time.Hour
+	batchSize = 100
+)
+
+// Worker в фоне рассылает опросы участникам закончившихся встреч
+type Worker struct {
+	Repository *FeedbackRepository
+	Notifier   notifier.Notifier
+	Config     *configs.Config
+	Logger     logger.LoggerInterface
+	Interval   time.Duration
+}
+
+// NewWorker создает обработчик, отправляющий опросы через уведомления
+func NewWorker(repo *FeedbackRepository, config *configs.Config, notify notifier.Notifier, log logger.LoggerInterface) *Worker {
+	return &Worker{
+		Repository: repo,
+		Notifier:   notify,
+		Config:     config,
+		Logger:     log,
+		Interval:   DefaultInterval,
+	}
+}
+
+// Run проверяет закончившиеся встречи до отмены контекста
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.RunOnce(ctx, time.Now().UTC()); err != nil {
+			w.Logger.Error("Feedback survey delivery failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce отправляет опросы по встречам, закончившимся к моменту now, и возвращает число отправленных
+func (w *Worker) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	recipients, err := w.Repository.PendingRecipients(now.Add(-Lookback), now, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, recipient := range recipients {
+		//опрос и уведомление сохраняются одной транзакцией: опрос не потеряется и не уйдет дважды
+		var created bool
+		err := w.Repository.DataBase.DB.Transaction(func(tx *gorm.DB) error {
+			txDB := &db.Db{DB: tx}
+			claimed, err := NewFeedbackRepository(txDB).CreateSent(recipient.EventID, recipient.UserID, now)
+			if err != nil || !claimed {
+				return err
+			}
+			notify := w.Notifier
+			if transactional, ok := notify.(notifier.Transactional); ok {
+				notify = transactional.WithTx(txDB)
+			}
+			//опрос относится к организации встречи
+			orgCtx := context.WithValue(ctx, middleware.ContextOrgIDKey, recipient.OrganizationID)
+			if err := notify.Notify(orgCtx, w.survey(recipient)); err != nil {
+				return err
+			}
+			created = true
+			return nil
+		})
+		if err != nil {
+			w.Logger.Error("Failed to send feedback survey", "event_id", recipient.EventID, "user_id", recipient.UserID, "error", err)
+			continue
+		}
+		if created {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// survey уведомление со ссылкой на опрос по встрече
+func (w *Worker) survey(recipient models.SurveyRecipient) notifier.Notification {
+	surveyLink := fmt.Sprintf("%s/event/%d/feedback", w.Config.Public.BaseURL, recipient.EventID)
+	to := notifier.Recipient{UserID: recipient.UserID, Email: recipient.Email, Locale: recipient.Locale, Timezone: recipient.Timezone}
+	ev := notifier.EventDetails{ID: recipient.EventID, Title: recipient.Title}
+	return notifier.FeedbackSurvey(to, ev, surveyLink)
+}
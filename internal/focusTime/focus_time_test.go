@@ -0,0 +1,86 @@
+package focusTime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+// понедельник
+var monday = time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+
+func at(day, hour, minute int) time.Time {
+	return monday.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestWeekStart(t *testing.T) {
+	require.Equal(t, monday, weekStart(at(0, 0, 0)))
+	require.Equal(t, monday, weekStart(at(3, 15, 0)))
+	require.Equal(t, monday, weekStart(at(6, 23, 59)))
+	require.Equal(t, monday.AddDate(0, 0, 7), weekStart(at(7, 1, 0)))
+}
+
+func TestWorkingSlots(t *testing.T) {
+	slots := workingSlots(at(0, 12, 0), monday.AddDate(0, 0, 7), time.UTC, nil)
+	// пять будних дней, понедельник начинается с полудня
+	require.Len(t, slots, 5)
+	require.Equal(t, at(0, 12, 0), slots[0].Start)
+	require.Equal(t, at(0, 17, 0), slots[0].End)
+	require.Equal(t, at(4, 9, 0), slots[4].Start)
+
+	// среда праздничная
+	slots = workingSlots(monday, monday.AddDate(0, 0, 7), time.UTC, map[string]bool{"2025-06-04": true})
+	require.Len(t, slots, 4)
+	require.Equal(t, at(3, 9, 0), slots[2].Start)
+
+	// в Москве (UTC+3) рабочий день идет с 6:00 до 14:00 UTC
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+	slots = workingSlots(monday, monday.AddDate(0, 0, 7), moscow, nil)
+	require.Len(t, slots, 5)
+	require.Equal(t, at(0, 6, 0), slots[0].Start)
+	require.Equal(t, at(0, 14, 0), slots[0].End)
+}
+
+func TestFreeSlots(t *testing.T) {
+	working := []models.TimeSlot{{Start: at(0, 9, 0), End: at(0, 17, 0)}}
+	busy := []models.TimeSlot{
+		{Start: at(0, 13, 0), End: at(0, 14, 0)},
+		{Start: at(0, 8, 0), End: at(0, 10, 0)},
+		// пересекается с предыдущей встречей
+		{Start: at(0, 13, 30), End: at(0, 15, 0)},
+	}
+	free := freeSlots(working, busy)
+	require.Equal(t, []models.TimeSlot{
+		{Start: at(0, 10, 0), End: at(0, 13, 0)},
+		{Start: at(0, 15, 0), End: at(0, 17, 0)},
+	}, free)
+}
+
+func TestPlan(t *testing.T) {
+	free := []models.TimeSlot{
+		{Start: at(0, 9, 0), End: at(0, 10, 30)},
+		{Start: at(1, 9, 0), End: at(1, 17, 0)},
+		{Start: at(2, 9, 0), End: at(2, 9, 45)},
+		{Start: at(3, 9, 0), End: at(3, 12, 0)},
+	}
+	blocks := plan(free, 600, 60)
+	// длинные промежутки первыми, блок не длиннее четырех часов, короче часа не ставится
+	require.Equal(t, []models.TimeSlot{
+		{Start: at(0, 9, 0), End: at(0, 10, 30)},
+		{Start: at(1, 9, 0), End: at(1, 13, 0)},
+		{Start: at(3, 9, 0), End: at(3, 12, 0)},
+	}, blocks)
+
+	require.Len(t, plan(free, 120, 60), 1)
+	require.Empty(t, plan(free, 30, 60))
+	require.Empty(t, plan(free, 0, 0))
+}
+
+func TestOverlaps(t *testing.T) {
+	slot := models.TimeSlot{Start: at(0, 10, 0), End: at(0, 11, 0)}
+	require.True(t, overlaps(slot, []models.TimeSlot{{Start: at(0, 10, 30), End: at(0, 12, 0)}}))
+	require.False(t, overlaps(slot, []models.TimeSlot{{Start: at(0, 11, 0), End: at(0, 12, 0)}}))
+}
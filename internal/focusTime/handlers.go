@@ -0,0 +1,139 @@
+package focusTime
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultMinBlockMinutes минимальная длина блока, если пользователь ее не указал
+const defaultMinBlockMinutes = 60
+
+type FocusTimeHandler struct {
+	FocusTimeRepository *FocusTimeRepository
+	HolidayRepository   *holiday.HolidayRepository
+	UserRepository      *user.UserRepository
+	JWTService          *jwt.JWT
+	Delegations         *delegation.DelegationRepository
+}
+
+type FocusTimeHandlerDeps struct {
+	FocusTimeRepository *FocusTimeRepository
+	HolidayRepository   *holiday.HolidayRepository
+	UserRepository      *user.UserRepository
+	JWTService          *jwt.JWT
+	Delegations         *delegation.DelegationRepository
+}
+
+// NewFocusTimeHandler регистрирует обработчики фокус-времени
+func NewFocusTimeHandler(mux *chi.Mux, deps FocusTimeHandlerDeps) {
+	handler := &FocusTimeHandler{
+		FocusTimeRepository: deps.FocusTimeRepository,
+		HolidayRepository:   deps.HolidayRepository,
+		UserRepository:      deps.UserRepository,
+		JWTService:          deps.JWTService,
+		Delegations:         deps.Delegations,
+	}
+	mux.Handle("GET /focus-time", middleware.IsAuthedAs(handler.Get(), handler.JWTService, handler.Delegations))
+	mux.Handle("PUT /focus-time", middleware.IsAuthedAs(handler.Set(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /focus-time", middleware.IsAuthedAs(handler.Delete(), handler.JWTService, handler.Delegations))
+}
+
+// Get возвращает цель пользователя и поставленные блоки
+func (h *FocusTimeHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		goal, err := h.FocusTimeRepository.FindGoal(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if goal == nil {
+			http.Error(w, "Focus time goal is not set", http.StatusNotFound)
+			return
+		}
+		h.respond(w, goal, http.StatusOK)
+	}
+}
+
+// Set задает недельную цель и сразу ставит блоки фокус-времени
+func (h *FocusTimeHandler) Set() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[FocusTimeRequest](w, r)
+		if err != nil {
+			return
+		}
+		owner, err := h.UserRepository.FindByid(userID)
+		if err != nil {
+			http.Error(w, "User not found", http.StatusUnauthorized)
+			return
+		}
+		minBlock := body.MinBlockMinutes
+		if minBlock == 0 {
+			minBlock = defaultMinBlockMinutes
+		}
+		goal, err := h.FocusTimeRepository.SaveGoal(&models.FocusTimeGoal{
+			UserID:          userID,
+			WeeklyMinutes:   body.WeeklyHours * 60,
+			MinBlockMinutes: minBlock,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if _, _, err := Schedule(h.FocusTimeRepository, h.HolidayRepository, goal, owner, time.Now().UTC()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.respond(w, goal, http.StatusOK)
+	}
+}
+
+// Delete отключает фокус-время и снимает будущие блоки
+func (h *FocusTimeHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err := h.FocusTimeRepository.DeleteGoal(userID, time.Now().UTC()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Focus time disabled", http.StatusOK)
+	}
+}
+
+// respond отдает цель вместе с блоками текущей и следующей недель
+func (h *FocusTimeHandler) respond(w http.ResponseWriter, goal *models.FocusTimeGoal, status int) {
+	from := weekStart(time.Now())
+	events, err := h.FocusTimeRepository.FocusEvents(goal.UserID, from, from.AddDate(0, 0, 7*weeksAhead))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	blocks := make([]models.TimeSlot, 0, len(events))
+	for _, ev := range events {
+		blocks = append(blocks, models.TimeSlot{Start: ev.StartDate, End: ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute)})
+	}
+	res.JsonResponse(w, FocusTimeResponse{Goal: goal, Blocks: blocks}, status)
+}
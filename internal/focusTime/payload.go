@@ -0,0 +1,16 @@
+package focusTime
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// FocusTimeRequest недельная цель фокус-времени
+type FocusTimeRequest struct {
+	WeeklyHours int `json:"weekly_hours" validate:"required,min=1,max=40"`
+	// MinBlockMinutes по умолчанию 60
+	MinBlockMinutes int `json:"min_block_minutes" validate:"omitempty,min=30,max=240"`
+}
+
+// FocusTimeResponse цель и поставленные блоки текущей и следующей недель
+type FocusTimeResponse struct {
+	Goal   *models.FocusTimeGoal `json:"goal"`
+	Blocks []models.TimeSlot     `json:"blocks"`
+}
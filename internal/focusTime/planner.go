@@ -0,0 +1,111 @@
+package focusTime
+
+import (
+	"sort"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/holidays"
+)
+
+const (
+	// maxBlockMinutes один блок не длиннее половины рабочего дня
+	maxBlockMinutes = 240
+	// slotStep шаг, по которому выравниваются начала блоков
+	slotStep = 15 * time.Minute
+)
+
+// workingSlots возвращает рабочие часы будних дней в периоде [from, to) по местному времени
+// пользователя в поясе location. Праздники пользователя (даты в формате holidays.DateLayout) пропускаются.
+func workingSlots(from, to time.Time, location *time.Location, holidayDates map[string]bool) []models.TimeSlot {
+	var slots []models.TimeSlot
+	local := from.In(location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		if holidayDates[day.Format(holidays.DateLayout)] {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), models.WorkdayStartHour, 0, 0, 0, location).UTC()
+		end := time.Date(day.Year(), day.Month(), day.Day(), models.WorkdayEndHour, 0, 0, 0, location).UTC()
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if start.Before(end) {
+			slots = append(slots, models.TimeSlot{Start: start, End: end})
+		}
+	}
+	return slots
+}
+
+// freeSlots вычитает занятые интервалы из рабочих
+func freeSlots(working, busy []models.TimeSlot) []models.TimeSlot {
+	sorted := append([]models.TimeSlot(nil), busy...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var free []models.TimeSlot
+	for _, slot := range working {
+		cursor := slot.Start
+		for _, b := range sorted {
+			if !b.End.After(cursor) || !b.Start.Before(slot.End) {
+				continue
+			}
+			if b.Start.After(cursor) {
+				free = append(free, models.TimeSlot{Start: cursor, End: b.Start})
+			}
+			cursor = b.End
+			if !cursor.Before(slot.End) {
+				break
+			}
+		}
+		if cursor.Before(slot.End) {
+			free = append(free, models.TimeSlot{Start: cursor, End: slot.End})
+		}
+	}
+	return free
+}
+
+// plan раскладывает needMinutes по свободным промежуткам, начиная с самых длинных,
+// чтобы фокус-время шло крупными блоками не короче minBlock минут
+func plan(free []models.TimeSlot, needMinutes, minBlock int) []models.TimeSlot {
+	gaps := append([]models.TimeSlot(nil), free...)
+	sort.SliceStable(gaps, func(i, j int) bool { return gaps[i].Minutes() > gaps[j].Minutes() })
+
+	var blocks []models.TimeSlot
+	for _, gap := range gaps {
+		if needMinutes <= 0 || needMinutes < minBlock {
+			break
+		}
+		minutes := min(gap.Minutes(), needMinutes, maxBlockMinutes)
+		if minutes < minBlock {
+			break
+		}
+		blocks = append(blocks, models.TimeSlot{Start: gap.Start, End: gap.Start.Add(time.Duration(minutes) * time.Minute)})
+		needMinutes -= minutes
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Start.Before(blocks[j].Start) })
+	return blocks
+}
+
+// overlaps сообщает, пересекается ли интервал хотя бы с одним из занятых
+func overlaps(slot models.TimeSlot, busy []models.TimeSlot) bool {
+	for _, b := range busy {
+		if slot.Start.Before(b.End) && b.Start.Before(slot.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart возвращает начало недели (понедельник 00:00 UTC), в которую попадает t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
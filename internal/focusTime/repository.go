@@ -0,0 +1,159 @@
+package focusTime
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FocusTitle название автоматически поставленных блоков
+const FocusTitle = "Focus"
+
+type FocusTimeRepository struct {
+	DataBase *db.Db
+}
+
+// NewFocusTimeRepository создает новый репозиторий фокус-времени
+func NewFocusTimeRepository(dataBase *db.Db) *FocusTimeRepository {
+	return &FocusTimeRepository{DataBase: dataBase}
+}
+
+// SaveGoal создает или обновляет недельную цель пользователя
+func (repo *FocusTimeRepository) SaveGoal(goal *models.FocusTimeGoal) (*models.FocusTimeGoal, error) {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"weekly_minutes", "min_block_minutes", "updated_at"}),
+		}).
+		Create(goal)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return goal, nil
+}
+
+// FindGoal возвращает цель пользователя или nil, если она не задана
+func (repo *FocusTimeRepository) FindGoal(userID uint) (*models.FocusTimeGoal, error) {
+	var goal models.FocusTimeGoal
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("user_id = ?", userID).
+		First(&goal)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &goal, nil
+}
+
+// FindGoals возвращает цели всех пользователей вместе с владельцами
+func (repo *FocusTimeRepository) FindGoals() ([]models.FocusTimeGoal, error) {
+	var goals []models.FocusTimeGoal
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Preload("User").
+		Order("user_id").
+		Find(&goals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return goals, nil
+}
+
+// DeleteGoal удаляет цель и будущие блоки фокус-времени пользователя
+func (repo *FocusTimeRepository) DeleteGoal(userID uint, now time.Time) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		//цель удаляется физически, чтобы ее можно было задать снова при уникальном user_id
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.FocusTimeGoal{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("creator_id = ? AND focus AND start_date >= ?", userID, now).Delete(&models.Event{}).Error
+	})
+}
+
+// HardBusy возвращает интервалы, которые фокус-время занять не может: встречи пользователя,
+// кроме отклоненных, и периоды отсутствия
+func (repo *FocusTimeRepository) HardBusy(userID uint, from, to time.Time) ([]models.TimeSlot, error) {
+	var events []models.Event
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Event{}).
+		Joins("JOIN event_participants ep ON ep.event_id = events.id AND ep.deleted_at IS NULL").
+		Joins("LEFT JOIN calendars c ON c.id = events.calendar_id AND c.deleted_at IS NULL").
+		Where("ep.user_id = ? AND NOT events.focus", userID).
+		Where("ep.status NOT IN ?", []models.EventStatus{models.StatusDecline, models.StatusOutOfOffice}).
+		Where("c.id IS NULL OR c.counts_toward_busy").
+		Where(`
+			(events.start_date, events.start_date + (events.duration || ' minutes')::interval)
+			OVERLAPS (?, ?)`,
+			from, to).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	var periods []models.OutOfOffice
+	err = repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("user_id = ? AND start_date < ? AND end_date > ?", userID, to, from).
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+	busy := make([]models.TimeSlot, 0, len(events)+len(periods))
+	for _, ev := range events {
+		busy = append(busy, models.TimeSlot{Start: ev.StartDate, End: ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute)})
+	}
+	for _, p := range periods {
+		busy = append(busy, models.TimeSlot{Start: p.StartDate, End: p.EndDate})
+	}
+	return busy, nil
+}
+
+// FocusEvents возвращает блоки фокус-времени пользователя, начинающиеся в периоде [from, to)
+func (repo *FocusTimeRepository) FocusEvents(userID uint, from, to time.Time) ([]models.Event, error) {
+	var events []models.Event
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("creator_id = ? AND focus AND start_date >= ? AND start_date < ?", userID, from, to).
+		Order("start_date").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// ReplaceFocusEvents удаляет устаревшие блоки и ставит новые одной транзакцией
+func (repo *FocusTimeRepository) ReplaceFocusEvents(owner *models.User, remove []uint, add []models.TimeSlot) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Where("id IN ? AND creator_id = ? AND focus", remove, owner.ID).Delete(&models.Event{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, slot := range add {
+			ev := models.NewEvent(FocusTitle, "", slot.Minutes(), owner.ID, slot.Start)
+			ev.OrganizationID = owner.OrganizationID
+			ev.Focus = true
+			if err := tx.Create(ev).Error; err != nil {
+				return err
+			}
+			participant := models.NewEventParticipant(ev.ID, owner.ID)
+			participant.Role = models.RoleOrganizer
+			participant.Status = models.StatusAccepted
+			if err := tx.Create(participant).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
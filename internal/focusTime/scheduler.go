@@ -0,0 +1,86 @@
+package focusTime
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/holidays"
+)
+
+// weeksAhead на сколько недель вперед, включая текущую, ставится фокус-время
+const weeksAhead = 2
+
+// Schedule приводит блоки фокус-времени пользователя к цели на текущую и следующую недели.
+// Блоки, на которые легли встречи, снимаются, недостающее время ставится в свободные промежутки
+// рабочих дней в часовом поясе владельца, праздники пользователя пропускаются. Возвращает число снятых и поставленных блоков.
+func Schedule(repo *FocusTimeRepository, holidayRepo *holiday.HolidayRepository, goal *models.FocusTimeGoal, owner *models.User, now time.Time) (removed, added int, err error) {
+	//новые блоки начинаются не раньше следующего шага сетки
+	from := now.UTC().Truncate(slotStep).Add(slotStep)
+	for week := 0; week < weeksAhead; week++ {
+		start := weekStart(now).AddDate(0, 0, 7*week)
+		end := start.AddDate(0, 0, 7)
+		if from.After(start) {
+			start = from
+		}
+		if !start.Before(end) {
+			continue
+		}
+		remove, add, err := reconcile(repo, holidayRepo, goal, owner.Location(), start, end)
+		if err != nil {
+			return removed, added, err
+		}
+		if len(remove) == 0 && len(add) == 0 {
+			continue
+		}
+		if err := repo.ReplaceFocusEvents(owner, remove, add); err != nil {
+			return removed, added, err
+		}
+		removed += len(remove)
+		added += len(add)
+	}
+	return removed, added, nil
+}
+
+// reconcile вычисляет, какие блоки недели снять и какие поставить в периоде [from, to)
+func reconcile(repo *FocusTimeRepository, holidayRepo *holiday.HolidayRepository, goal *models.FocusTimeGoal, location *time.Location, from, to time.Time) ([]uint, []models.TimeSlot, error) {
+	week := weekStart(from)
+	existing, err := repo.FocusEvents(goal.UserID, week, week.AddDate(0, 0, 7))
+	if err != nil {
+		return nil, nil, err
+	}
+	busy, err := repo.HardBusy(goal.UserID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	need := goal.WeeklyMinutes
+	var remove []uint
+	var kept []models.TimeSlot
+	for _, ev := range existing {
+		slot := models.TimeSlot{Start: ev.StartDate, End: ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute)}
+		if slot.Start.Before(from) {
+			//уже начавшиеся блоки засчитываются в цель и не переносятся
+			need -= slot.Minutes()
+			continue
+		}
+		if overlaps(slot, busy) || slot.Minutes() > need {
+			remove = append(remove, ev.ID)
+			continue
+		}
+		kept = append(kept, slot)
+		need -= slot.Minutes()
+	}
+
+	userHolidays, err := holidayRepo.HolidaysFor([]uint{goal.UserID}, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	holidayDates := make(map[string]bool, len(userHolidays))
+	for _, day := range userHolidays {
+		holidayDates[day.Date.Format(holidays.DateLayout)] = true
+	}
+
+	free := freeSlots(workingSlots(from, to, location, holidayDates), append(busy, kept...))
+	return remove, plan(free, need, goal.MinBlockMinutes), nil
+}
@@ -0,0 +1,64 @@
+package focusTime
+
+import (
+	"context"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+)
+
+// DefaultInterval период пересчета фокус-времени: новые встречи вытесняют блоки не позже чем через него
+const DefaultInterval = 5 * time.Minute
+
+// Worker в фоне ставит и переносит блоки фокус-времени всех пользователей с целью
+type Worker struct {
+	Repository *FocusTimeRepository
+	Holidays   *holiday.HolidayRepository
+	Logger     logger.LoggerInterface
+	Interval   time.Duration
+}
+
+// NewWorker создает фоновую задачу фокус-времени
+func NewWorker(repo *FocusTimeRepository, holidayRepo *holiday.HolidayRepository, log logger.LoggerInterface) *Worker {
+	return &Worker{
+		Repository: repo,
+		Holidays:   holidayRepo,
+		Logger:     log,
+		Interval:   DefaultInterval,
+	}
+}
+
+// Run пересчитывает фокус-время до отмены контекста
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if err := w.RunOnce(time.Now().UTC()); err != nil {
+			w.Logger.Error("Focus time scheduling failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce пересчитывает блоки по всем целям. Ошибка по одному пользователю не останавливает остальных.
+func (w *Worker) RunOnce(now time.Time) error {
+	goals, err := w.Repository.FindGoals()
+	if err != nil {
+		return err
+	}
+	for i := range goals {
+		goal := &goals[i]
+		if goal.User == nil {
+			continue
+		}
+		if _, _, err := Schedule(w.Repository, w.Holidays, goal, goal.User, now); err != nil {
+			w.Logger.Error("Failed to schedule focus time", "user_id", goal.UserID, "error", err)
+		}
+	}
+	return nil
+}
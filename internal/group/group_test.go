@@ -0,0 +1,129 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGroupOwnerIsManager(t *testing.T) {
+	group := models.NewGroup(7, 1, "Backend", "")
+	require.Len(t, group.Members, 1)
+	require.Equal(t, uint(7), group.Members[0].UserID)
+	require.True(t, group.Members[0].IsManager)
+}
+
+func TestMemberIDs(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "user_id" FROM "group_members" WHERE group_id = $1 AND "group_members"."deleted_at" IS NULL ORDER BY user_id`)).
+		WithArgs(uint(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(5))
+
+	repo := NewGroupRepository(&db.Db{DB: gormDB})
+	ids, err := repo.MemberIDs(1)
+	require.NoError(t, err)
+	require.Equal(t, []uint{2, 5}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsManager(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "group_members" WHERE (group_id = $1 AND user_id = $2 AND is_manager) AND "group_members"."deleted_at" IS NULL`)).
+		WithArgs(uint(1), uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	repo := NewGroupRepository(&db.Db{DB: gormDB})
+	isManager, err := repo.IsManager(1, 3)
+	require.NoError(t, err)
+	require.False(t, isManager)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindUpcomingLinks(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	start := now.Add(24 * time.Hour)
+
+	mock.ExpectQuery(`SELECT .* FROM "event_groups" LEFT JOIN "events" "Event" .* WHERE \(event_groups.group_id = \$1 AND event_groups.include_new_members\) AND "Event".start_date > \$2`).
+		WithArgs(uint(1), now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "group_id", "role", "include_new_members", "Event__id", "Event__start_date", "Event__duration"}).
+			AddRow(1, 10, 1, models.RoleOptional, true, 10, start, 30))
+
+	repo := NewGroupRepository(&db.Db{DB: gormDB})
+	links, err := repo.FindUpcomingLinks(1, now)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, models.RoleOptional, links[0].Role)
+	require.NotNil(t, links[0].Event)
+	require.Equal(t, 30, links[0].Event.Duration)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+type failingInviter struct {
+	calls int
+}
+
+func (f *failingInviter) InviteGroupMember(ctx context.Context, tx *db.Db, link models.EventGroup, userID uint) (models.EventStatus, error) {
+	f.calls++
+	return "", errors.New("outbox unavailable")
+}
+
+func TestAddMemberRollsBackWhenInviteFails(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Now().Add(24 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "group_members" WHERE (group_id = $1 AND user_id = $2 AND is_manager)`)).
+		WithArgs(uint(1), uint(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE (id = $1 AND organization_id = $2)`)).
+		WithArgs(uint(3), uint(2), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id"}).AddRow(3, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "group_members" WHERE (group_id = $1 AND user_id = $2)`)).
+		WithArgs(uint(1), uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "group_members"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectQuery(`SELECT .* FROM "event_groups" LEFT JOIN "events" "Event"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "group_id", "role", "include_new_members", "Event__id", "Event__start_date", "Event__duration"}).
+			AddRow(1, 10, 1, models.RoleRequired, true, 10, start, 30))
+	//участие в группе не должно сохраниться без приглашений
+	mock.ExpectRollback()
+
+	inviter := &failingInviter{}
+	handler := &GroupHandler{
+		GroupRepository: NewGroupRepository(&db.Db{DB: gormDB}),
+		UserRepository:  user.NewUserRepository(&db.Db{DB: gormDB}),
+		Invites:         inviter,
+	}
+	req := httptest.NewRequest(http.MethodPost, "/groups/1/members", strings.NewReader(`{"user_id":3}`))
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "1")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	ctx = context.WithValue(ctx, middleware.ContextUserIDKey, uint(7))
+	ctx = context.WithValue(ctx, middleware.ContextOrgIDKey, uint(2))
+	rec := httptest.NewRecorder()
+	handler.AddMember().ServeHTTP(rec, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, 1, inviter.calls)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,292 @@
+package group
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+// EventInviter приглашает участника группы в событие внутри транзакции tx
+// и возвращает его статус, пустой статус означает, что он уже участвует
+type EventInviter interface {
+	InviteGroupMember(ctx context.Context, tx *db.Db, link models.EventGroup, userID uint) (models.EventStatus, error)
+}
+
+type GroupHandler struct {
+	GroupRepository *GroupRepository
+	UserRepository  *user.UserRepository
+	Invites         EventInviter
+	JWTService      *jwt.JWT
+	Delegations     *delegation.DelegationRepository
+}
+
+type GroupHandlerDeps struct {
+	GroupRepository *GroupRepository
+	UserRepository  *user.UserRepository
+	Invites         EventInviter
+	JWTService      *jwt.JWT
+	Delegations     *delegation.DelegationRepository
+}
+
+// NewGroupHandler регистрирует обработчики групп
+func NewGroupHandler(mux *chi.Mux, deps GroupHandlerDeps) {
+	handler := &GroupHandler{
+		GroupRepository: deps.GroupRepository,
+		UserRepository:  deps.UserRepository,
+		Invites:         deps.Invites,
+		JWTService:      deps.JWTService,
+		Delegations:     deps.Delegations,
+	}
+	mux.Handle("GET /groups", middleware.IsAuthedAs(handler.GetGroups(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /groups", middleware.IsAuthedAs(handler.CreateGroup(), handler.JWTService, handler.Delegations))
+	mux.Handle("GET /groups/{id}", middleware.IsAuthedAs(handler.GetGroup(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /groups/{id}", middleware.IsAuthedAs(handler.DeleteGroup(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /groups/{id}/members", middleware.IsAuthedAs(handler.AddMember(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /groups/{id}/members/{user_id}", middleware.IsAuthedAs(handler.RemoveMember(), handler.JWTService, handler.Delegations))
+}
+
+// GetGroups возвращает группы, в которых состоит текущий пользователь
+func (h *GroupHandler) GetGroups() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		groups, err := h.GroupRepository.FindByMember(userID)
+		if err != nil {
+			http.Error(w, "Failed to fetch groups", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, groups, http.StatusOK)
+	}
+}
+
+// CreateGroup создает группу, текущий пользователь становится ее менеджером
+func (h *GroupHandler) CreateGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[GroupRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		newGroup := models.NewGroup(userID, orgID, body.Name, body.Description)
+		//собираем состав группы без повторов, только из своей организации
+		added := map[uint]int{userID: 0}
+		for _, memberID := range append(body.Managers, body.Members...) {
+			if _, ok := added[memberID]; ok {
+				continue
+			}
+			if _, err := h.UserRepository.FindInOrganization(memberID, orgID); err != nil {
+				http.Error(w, "User not found", http.StatusBadRequest)
+				return
+			}
+			added[memberID] = len(newGroup.Members)
+			newGroup.Members = append(newGroup.Members, models.GroupMember{UserID: memberID})
+		}
+		for _, managerID := range body.Managers {
+			newGroup.Members[added[managerID]].IsManager = true
+		}
+		createdGroup, err := h.GroupRepository.Create(newGroup)
+		if err != nil {
+			http.Error(w, "Not possible to create group", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, createdGroup, http.StatusCreated)
+	}
+}
+
+// GetGroup возвращает группу с участниками, доступно только участникам группы
+func (h *GroupHandler) GetGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		groupID, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		foundGroup, err := h.GroupRepository.FindById(groupID)
+		if err != nil {
+			http.Error(w, "Group not found", http.StatusNotFound)
+			return
+		}
+		isMember, err := h.GroupRepository.IsMember(groupID, userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !isMember {
+			http.Error(w, "You are not a member of this group", http.StatusForbidden)
+			return
+		}
+		res.JsonResponse(w, foundGroup, http.StatusOK)
+	}
+}
+
+// DeleteGroup удаляет группу, доступно только менеджерам
+func (h *GroupHandler) DeleteGroup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupID, ok := h.managedGroup(w, r)
+		if !ok {
+			return
+		}
+		if err := h.GroupRepository.DeleteById(groupID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Group deleted", http.StatusOK)
+	}
+}
+
+// AddMember добавляет участника в группу и приглашает его в будущие события,
+// где группа была приглашена с добавлением новых участников
+func (h *GroupHandler) AddMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupID, ok := h.managedGroup(w, r)
+		if !ok {
+			return
+		}
+		body, err := request.HandelBody[MemberRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		if _, err := h.UserRepository.FindInOrganization(body.UserID, orgID); err != nil {
+			http.Error(w, "User not found", http.StatusBadRequest)
+			return
+		}
+		isMember, err := h.GroupRepository.IsMember(groupID, body.UserID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if isMember {
+			http.Error(w, "User is already a member of this group", http.StatusConflict)
+			return
+		}
+		//участие в группе и приглашения в ее события сохраняются одной транзакцией
+		tx := h.GroupRepository.DataBase.DB.Begin()
+		if tx.Error != nil {
+			http.Error(w, tx.Error.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer tx.Rollback()
+		txDB := &db.Db{DB: tx}
+		member, err := NewGroupRepository(txDB).AddMember(groupID, body.UserID, body.IsManager)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		invites, err := h.inviteToUpcomingEvents(r.Context(), txDB, groupID, body.UserID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := tx.Commit().Error; err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, &MemberResponse{Member: member, Events: invites}, http.StatusCreated)
+	}
+}
+
+// RemoveMember удаляет участника из группы: менеджер может удалить любого, участник только себя
+func (h *GroupHandler) RemoveMember() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		groupID, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		memberID, err := convert.ParseId(r, "user_id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if memberID != userID {
+			isManager, err := h.GroupRepository.IsManager(groupID, userID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !isManager {
+				http.Error(w, "Only group managers can remove members", http.StatusForbidden)
+				return
+			}
+		}
+		if err := h.GroupRepository.RemoveMember(groupID, memberID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Member removed", http.StatusOK)
+	}
+}
+
+// managedGroup читает ID группы из URL и проверяет, что текущий пользователь ее менеджер
+func (h *GroupHandler) managedGroup(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, false
+	}
+	groupID, err := convert.ParseId(r, "id")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return 0, false
+	}
+	isManager, err := h.GroupRepository.IsManager(groupID, userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return 0, false
+	}
+	if !isManager {
+		http.Error(w, "Only group managers can change the group", http.StatusForbidden)
+		return 0, false
+	}
+	return groupID, true
+}
+
+// inviteToUpcomingEvents добавляет нового участника группы в будущие события группы в транзакции tx
+func (h *GroupHandler) inviteToUpcomingEvents(ctx context.Context, tx *db.Db, groupID, userID uint) ([]EventInvite, error) {
+	links, err := NewGroupRepository(tx).FindUpcomingLinks(groupID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var invites []EventInvite
+	for _, link := range links {
+		status, err := h.Invites.InviteGroupMember(ctx, tx, link, userID)
+		if err != nil {
+			return nil, err
+		}
+		if status == "" {
+			continue
+		}
+		invites = append(invites, EventInvite{EventID: link.EventID, Status: status})
+	}
+	return invites, nil
+}
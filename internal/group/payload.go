@@ -0,0 +1,29 @@
+package group
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// GroupRequest данные для создания группы
+type GroupRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description"`
+	Members     []uint `json:"members"`
+	Managers    []uint `json:"managers"`
+}
+
+// MemberRequest данные для добавления участника в группу
+type MemberRequest struct {
+	UserID    uint `json:"user_id" validate:"required"`
+	IsManager bool `json:"is_manager"`
+}
+
+// MemberResponse добавленный участник и его статусы в будущих событиях группы
+type MemberResponse struct {
+	Member *models.GroupMember `json:"member"`
+	Events []EventInvite       `json:"events,omitempty"`
+}
+
+// EventInvite приглашение нового участника в событие группы
+type EventInvite struct {
+	EventID uint               `json:"event_id"`
+	Status  models.EventStatus `json:"status"`
+}
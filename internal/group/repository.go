@@ -0,0 +1,159 @@
+package group
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type GroupRepository struct {
+	DataBase *db.Db
+}
+
+// NewGroupRepository создает новый репозиторий групп
+func NewGroupRepository(dataBase *db.Db) *GroupRepository {
+	return &GroupRepository{DataBase: dataBase}
+}
+
+// Create создает группу вместе с участниками
+func (repo *GroupRepository) Create(group *models.Group) (*models.Group, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(group)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return group, nil
+}
+
+// FindById находит группу по ID вместе с участниками
+func (repo *GroupRepository) FindById(id uint) (*models.Group, error) {
+	var group models.Group
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Preload("Members").
+		First(&group, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &group, nil
+}
+
+// FindByMember возвращает группы, в которых состоит пользователь
+func (repo *GroupRepository) FindByMember(userID uint) ([]models.Group, error) {
+	var groups []models.Group
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Joins("JOIN group_members gm ON gm.group_id = groups.id AND gm.deleted_at IS NULL").
+		Where("gm.user_id = ?", userID).
+		Order("groups.id").
+		Find(&groups)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return groups, nil
+}
+
+// MemberIDs возвращает ID участников группы
+func (repo *GroupRepository) MemberIDs(groupID uint) ([]uint, error) {
+	var ids []uint
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.GroupMember{}).
+		Where("group_id = ?", groupID).
+		Order("user_id").
+		Pluck("user_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+// IsMember проверяет, состоит ли пользователь в группе
+func (repo *GroupRepository) IsMember(groupID, userID uint) (bool, error) {
+	var count int64
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.GroupMember{}).
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsManager проверяет, может ли пользователь менять состав группы
+func (repo *GroupRepository) IsManager(groupID, userID uint) (bool, error) {
+	var count int64
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.GroupMember{}).
+		Where("group_id = ? AND user_id = ? AND is_manager", groupID, userID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AddMember добавляет пользователя в группу
+func (repo *GroupRepository) AddMember(groupID, userID uint, isManager bool) (*models.GroupMember, error) {
+	member := &models.GroupMember{GroupID: groupID, UserID: userID, IsManager: isManager}
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(member)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return member, nil
+}
+
+// RemoveMember удаляет пользователя из группы
+func (repo *GroupRepository) RemoveMember(groupID, userID uint) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Where("group_id = ? AND user_id = ?", groupID, userID).
+		Delete(&models.GroupMember{}).Error
+}
+
+// DeleteById удаляет группу, ее состав и связи с приглашениями.
+// Уже добавленные в события участники остаются в событиях.
+func (repo *GroupRepository) DeleteById(id uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("group_id = ?", id).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&models.EventGroup{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Group{}, id).Error
+	})
+}
+
+// LinkEvent связывает приглашение на событие с группой
+func (repo *GroupRepository) LinkEvent(eventID, groupID uint, role models.ParticipantRole, includeNewMembers bool) error {
+	link := &models.EventGroup{
+		EventID:           eventID,
+		GroupID:           groupID,
+		Role:              role,
+		IncludeNewMembers: includeNewMembers,
+	}
+	return repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(link).Error
+}
+
+// FindUpcomingLinks возвращает связи группы с событиями, которые начнутся после from
+// и в которые нужно добавлять новых участников группы
+func (repo *GroupRepository) FindUpcomingLinks(groupID uint, from time.Time) ([]models.EventGroup, error) {
+	var links []models.EventGroup
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Joins("Event").
+		Where("event_groups.group_id = ? AND event_groups.include_new_members", groupID).
+		Where(`"Event".start_date > ?`, from).
+		Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
@@ -0,0 +1,220 @@
+package holiday
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/holidays"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUploadSize предельный размер загружаемого файла календаря
+const maxUploadSize = 1 << 20
+
+type HolidayHandler struct {
+	HolidayRepository *HolidayRepository
+	UserRepository    *user.UserRepository
+	GroupRepository   *group.GroupRepository
+	JWTService        *jwt.JWT
+}
+
+type HolidayHandlerDeps struct {
+	HolidayRepository *HolidayRepository
+	UserRepository    *user.UserRepository
+	GroupRepository   *group.GroupRepository
+	JWTService        *jwt.JWT
+}
+
+// NewHolidayHandler регистрирует обработчики праздничных календарей
+func NewHolidayHandler(mux *chi.Mux, deps HolidayHandlerDeps) {
+	handler := &HolidayHandler{
+		HolidayRepository: deps.HolidayRepository,
+		UserRepository:    deps.UserRepository,
+		GroupRepository:   deps.GroupRepository,
+		JWTService:        deps.JWTService,
+	}
+	mux.Handle("GET /holiday-calendars", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("GET /holiday-calendars/{id}", middleware.IsAuthed(handler.Get(), handler.JWTService))
+	mux.Handle("POST /holiday-calendars", middleware.IsAuthed(handler.Upload(), handler.JWTService))
+	mux.Handle("POST /holiday-calendars/{id}/assignments", middleware.IsAuthed(handler.Assign(), handler.JWTService))
+	mux.Handle("DELETE /holiday-calendars/{id}", middleware.IsAuthed(handler.Delete(), handler.JWTService))
+}
+
+// List возвращает праздничные календари организации
+func (h *HolidayHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := middleware.OrganizationID(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		calendars, err := h.HolidayRepository.FindByOrganization(orgID)
+		if err != nil {
+			http.Error(w, "Failed to fetch holiday calendars", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, calendars, http.StatusOK)
+	}
+}
+
+// Get возвращает календарь организации со списком праздников
+func (h *HolidayHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := middleware.OrganizationID(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		calendar, err := h.HolidayRepository.FindInOrganization(id, orgID)
+		if err != nil {
+			http.Error(w, "Holiday calendar not found", http.StatusNotFound)
+			return
+		}
+		res.JsonResponse(w, calendar, http.StatusOK)
+	}
+}
+
+// Upload загружает календарь из файла ICS или JSON (multipart: name, file), доступно администраторам
+func (h *HolidayHandler) Upload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := h.currentAdmin(w, r)
+		if !ok {
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+		if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+			http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+			return
+		}
+		name := strings.TrimSpace(r.FormValue("name"))
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "file is required", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if name == "" {
+			name = header.Filename
+		}
+		days, err := holidays.Parse(header.Filename, file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		calendar := &models.HolidayCalendar{
+			OrganizationID: admin.OrganizationID,
+			Name:           name,
+			Holidays:       make([]models.Holiday, 0, len(days)),
+		}
+		for _, day := range days {
+			calendar.Holidays = append(calendar.Holidays, models.Holiday{Date: day.Date, Name: day.Name})
+		}
+		createdCalendar, err := h.HolidayRepository.Create(calendar)
+		if err != nil {
+			http.Error(w, "Not possible to save holiday calendar", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, createdCalendar, http.StatusCreated)
+	}
+}
+
+// Assign назначает календарь пользователю или группе своей организации, доступно администраторам
+func (h *HolidayHandler) Assign() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := h.currentAdmin(w, r)
+		if !ok {
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body, err := request.HandelBody[AssignRequest](w, r)
+		if err != nil {
+			return
+		}
+		if _, err := h.HolidayRepository.FindInOrganization(id, admin.OrganizationID); err != nil {
+			http.Error(w, "Holiday calendar not found", http.StatusNotFound)
+			return
+		}
+		if body.UserID != nil {
+			if _, err := h.UserRepository.FindInOrganization(*body.UserID, admin.OrganizationID); err != nil {
+				http.Error(w, "User not found", http.StatusNotFound)
+				return
+			}
+		} else {
+			foundGroup, err := h.GroupRepository.FindById(*body.GroupID)
+			if err != nil || foundGroup.OrganizationID != admin.OrganizationID {
+				http.Error(w, "Group not found", http.StatusNotFound)
+				return
+			}
+		}
+		assignment, err := h.HolidayRepository.Assign(&models.HolidayAssignment{
+			CalendarID: id,
+			UserID:     body.UserID,
+			GroupID:    body.GroupID,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, assignment, http.StatusCreated)
+	}
+}
+
+// Delete удаляет календарь организации, доступно администраторам
+func (h *HolidayHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := h.currentAdmin(w, r)
+		if !ok {
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := h.HolidayRepository.FindInOrganization(id, admin.OrganizationID); err != nil {
+			http.Error(w, "Holiday calendar not found", http.StatusNotFound)
+			return
+		}
+		if err := h.HolidayRepository.DeleteById(id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Holiday calendar deleted", http.StatusOK)
+	}
+}
+
+// currentAdmin загружает авторизованного пользователя и проверяет, что он администратор организации
+func (h *HolidayHandler) currentAdmin(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	foundUser, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return nil, false
+	}
+	if foundUser.OrganizationID == models.DefaultOrganizationID || foundUser.OrgRole != models.OrgRoleAdmin {
+		http.Error(w, "Only organization admins can manage holiday calendars", http.StatusForbidden)
+		return nil, false
+	}
+	return foundUser, true
+}
@@ -0,0 +1,46 @@
+package holiday
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolidaysFor(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	holidayDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT COALESCE(a.user_id, gm.user_id) AS user_id, h.date, h.name FROM holidays h `+
+		`JOIN holiday_assignments a ON a.calendar_id = h.calendar_id AND a.deleted_at IS NULL `+
+		`LEFT JOIN group_members gm ON gm.group_id = a.group_id AND gm.deleted_at IS NULL `+
+		`WHERE (h.deleted_at IS NULL AND h.date BETWEEN $1 AND $2) AND (a.user_id IN ($3,$4) OR gm.user_id IN ($5,$6)) ORDER BY h.date`)).
+		WithArgs("2025-01-01", "2025-01-01", uint(1), uint(2), uint(1), uint(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "date", "name"}).
+			AddRow(2, holidayDate, "Новый год"))
+
+	repo := NewHolidayRepository(&db.Db{DB: gormDB})
+	holidays, err := repo.HolidaysFor([]uint{1, 2}, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, holidays, 1)
+	require.Equal(t, uint(2), holidays[0].UserID)
+	require.Equal(t, "Новый год", holidays[0].Name)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHolidaysForNoUsers(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	repo := NewHolidayRepository(&db.Db{DB: gormDB})
+	holidays, err := repo.HolidaysFor(nil, time.Now(), time.Now())
+	require.NoError(t, err)
+	require.Empty(t, holidays)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
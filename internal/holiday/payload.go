@@ -0,0 +1,7 @@
+package holiday
+
+// AssignRequest назначение календаря: указывается пользователь или группа
+type AssignRequest struct {
+	UserID  *uint `json:"user_id" validate:"required_without=GroupID,excluded_with=GroupID"`
+	GroupID *uint `json:"group_id" validate:"required_without=UserID"`
+}
@@ -0,0 +1,102 @@
+package holiday
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type HolidayRepository struct {
+	DataBase *db.Db
+}
+
+// NewHolidayRepository создает новый репозиторий праздничных календарей
+func NewHolidayRepository(dataBase *db.Db) *HolidayRepository {
+	return &HolidayRepository{DataBase: dataBase}
+}
+
+// Create сохраняет календарь вместе с праздниками
+func (repo *HolidayRepository) Create(calendar *models.HolidayCalendar) (*models.HolidayCalendar, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(calendar)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendar, nil
+}
+
+// FindByOrganization возвращает календари организации без списка праздников
+func (repo *HolidayRepository) FindByOrganization(organizationID uint) ([]models.HolidayCalendar, error) {
+	var calendars []models.HolidayCalendar
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("organization_id = ?", organizationID).
+		Order("id").
+		Find(&calendars)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return calendars, nil
+}
+
+// FindInOrganization находит календарь с праздниками только внутри организации
+func (repo *HolidayRepository) FindInOrganization(id, organizationID uint) (*models.HolidayCalendar, error) {
+	var calendar models.HolidayCalendar
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Preload("Holidays", func(db *gorm.DB) *gorm.DB { return db.Order("date") }).
+		Where("organization_id = ?", organizationID).
+		First(&calendar, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &calendar, nil
+}
+
+// Assign назначает календарь пользователю или группе
+func (repo *HolidayRepository) Assign(assignment *models.HolidayAssignment) (*models.HolidayAssignment, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(assignment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignment, nil
+}
+
+// DeleteById удаляет календарь, его праздники и назначения
+func (repo *HolidayRepository) DeleteById(id uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("calendar_id = ?", id).Delete(&models.HolidayAssignment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("calendar_id = ?", id).Delete(&models.Holiday{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.HolidayCalendar{}, id).Error
+	})
+}
+
+// HolidaysFor возвращает праздники пользователей в периоде дат [from, to] включительно.
+// Учитываются календари, назначенные пользователю напрямую и через его группы.
+// По ним предупреждают о встречах в праздник и пропускают праздники при подборе фокус-времени.
+func (repo *HolidayRepository) HolidaysFor(userIDs []uint, from, to time.Time) ([]models.UserHoliday, error) {
+	var holidays []models.UserHoliday
+	if len(userIDs) == 0 {
+		return holidays, nil
+	}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("holidays h").
+		Select("DISTINCT COALESCE(a.user_id, gm.user_id) AS user_id, h.date, h.name").
+		Joins("JOIN holiday_assignments a ON a.calendar_id = h.calendar_id AND a.deleted_at IS NULL").
+		Joins("LEFT JOIN group_members gm ON gm.group_id = a.group_id AND gm.deleted_at IS NULL").
+		Where("h.deleted_at IS NULL AND h.date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Where("a.user_id IN ? OR gm.user_id IN ?", userIDs, userIDs).
+		Order("h.date").
+		Scan(&holidays)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return holidays, nil
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ParticipantAttendance присутствие участника на встрече
+type ParticipantAttendance struct {
+	UserID      uint        `json:"user_id"`
+	UserName    string      `json:"user_name"`
+	Status      EventStatus `json:"status"`
+	Attended    *bool       `json:"attended"`
+	CheckedInAt *time.Time  `json:"checked_in_at"`
+}
+
+// AttendanceSummary сводка присутствия по событию среди участников, принявших приглашение.
+// Неявкой считается участник, которого не отметили присутствующим.
+type AttendanceSummary struct {
+	EventID  uint `json:"event_id"`
+	Accepted int  `json:"accepted"`
+	Attended int  `json:"attended"`
+	NoShows  int  `json:"no_shows"`
+}
+
+// Rate доля пришедших среди принявших приглашение
+func (s AttendanceSummary) Rate() float64 {
+	if s.Accepted == 0 {
+		return 0
+	}
+	return float64(s.Attended) / float64(s.Accepted)
+}
+
+// UserAttendance присутствие пользователя на прошедших встречах, которые он принял
+type UserAttendance struct {
+	UserID   uint `json:"user_id"`
+	Accepted int  `json:"accepted"`
+	Attended int  `json:"attended"`
+	NoShows  int  `json:"no_shows"`
+	// Rate доля посещенных встреч
+	Rate float64 `json:"rate" gorm:"-"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "gorm.io/gorm"
+
+// Видимость событий календаря для тех, кому он открыт
+type CalendarVisibility string
+
+const (
+	// VisibilityShared события видны в пределах выданного доступа
+	VisibilityShared CalendarVisibility = "shared"
+	// VisibilityPrivate посторонние видят только занятость, даже при доступе к подробностям
+	VisibilityPrivate CalendarVisibility = "private"
+)
+
+// Calendar календарь пользователя, например "Работа", "Личное" или "Дежурства"
+type Calendar struct {
+	gorm.Model
+	OwnerID           uint               `json:"owner_id" gorm:"not null;index"`
+	Name              string             `json:"name" gorm:"not null"`
+	Color             string             `json:"color"`
+	DefaultVisibility CalendarVisibility `json:"default_visibility" gorm:"type:varchar(16);not null"`
+	// CountsTowardBusy учитываются ли события календаря при проверке занятости
+	CountsTowardBusy bool `json:"counts_toward_busy" gorm:"not null"`
+	// Связи
+	Owner *User `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+}
+
+// NewCalendar создает новый календарь
+func NewCalendar(ownerID uint, name, color string, visibility CalendarVisibility, countsTowardBusy bool) *Calendar {
+	if visibility == "" {
+		visibility = VisibilityShared
+	}
+	return &Calendar{
+		OwnerID:           ownerID,
+		Name:              name,
+		Color:             color,
+		DefaultVisibility: visibility,
+		CountsTowardBusy:  countsTowardBusy,
+	}
+}
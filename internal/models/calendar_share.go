@@ -0,0 +1,77 @@
+package models
+
+import "gorm.io/gorm"
+
+// Уровни доступа к чужому календарю
+type ShareLevel string
+
+const (
+	ShareFreeBusy ShareLevel = "free_busy"
+	ShareDetails  ShareLevel = "details"
+	ShareEdit     ShareLevel = "edit"
+)
+
+var shareRanks = map[ShareLevel]int{
+	ShareFreeBusy: 1,
+	ShareDetails:  2,
+	ShareEdit:     3,
+}
+
+// IsValid проверяет, что уровень доступа известен
+func (l ShareLevel) IsValid() bool {
+	_, ok := shareRanks[l]
+	return ok
+}
+
+// Allows сообщает, покрывает ли уровень доступа требуемый уровень
+func (l ShareLevel) Allows(required ShareLevel) bool {
+	return l.IsValid() && shareRanks[l] >= shareRanks[required]
+}
+
+// Max возвращает более широкий из двух уровней доступа
+func (l ShareLevel) Max(other ShareLevel) ShareLevel {
+	if shareRanks[other] > shareRanks[l] {
+		return other
+	}
+	return l
+}
+
+// Cap ограничивает уровень доступа сверху, отсутствие доступа остается отсутствием
+func (l ShareLevel) Cap(limit ShareLevel) ShareLevel {
+	if shareRanks[l] > shareRanks[limit] {
+		return limit
+	}
+	return l
+}
+
+// CalendarShare доступ к календарю владельца, выданный пользователю или группе.
+// Заполняется ровно одно из полей GranteeUserID и GranteeGroupID.
+type CalendarShare struct {
+	gorm.Model
+	OwnerID        uint       `json:"owner_id" gorm:"not null;index"`
+	GranteeUserID  *uint      `json:"grantee_user_id,omitempty" gorm:"index"`
+	GranteeGroupID *uint      `json:"grantee_group_id,omitempty" gorm:"index"`
+	Level          ShareLevel `json:"level" gorm:"type:varchar(16);not null"`
+	// Связи
+	Owner        *User  `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+	GranteeUser  *User  `json:"-" gorm:"foreignKey:GranteeUserID;constraint:OnDelete:CASCADE"`
+	GranteeGroup *Group `json:"-" gorm:"foreignKey:GranteeGroupID;constraint:OnDelete:CASCADE"`
+}
+
+// NewUserCalendarShare создает доступ к календарю для пользователя
+func NewUserCalendarShare(ownerID, granteeUserID uint, level ShareLevel) *CalendarShare {
+	return &CalendarShare{
+		OwnerID:       ownerID,
+		GranteeUserID: &granteeUserID,
+		Level:         level,
+	}
+}
+
+// NewGroupCalendarShare создает доступ к календарю для всех участников группы
+func NewGroupCalendarShare(ownerID, granteeGroupID uint, level ShareLevel) *CalendarShare {
+	return &CalendarShare{
+		OwnerID:        ownerID,
+		GranteeGroupID: &granteeGroupID,
+		Level:          level,
+	}
+}
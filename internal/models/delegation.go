@@ -0,0 +1,76 @@
+package models
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Уровни делегирования календаря
+type DelegationLevel string
+
+const (
+	DelegationRead   DelegationLevel = "read"
+	DelegationWrite  DelegationLevel = "write"
+	DelegationManage DelegationLevel = "manage"
+)
+
+var delegationRanks = map[DelegationLevel]int{
+	DelegationRead:   1,
+	DelegationWrite:  2,
+	DelegationManage: 3,
+}
+
+// IsValid проверяет, что уровень делегирования известен
+func (l DelegationLevel) IsValid() bool {
+	_, ok := delegationRanks[l]
+	return ok
+}
+
+// Allows сообщает, покрывает ли уровень делегирования требуемый уровень
+func (l DelegationLevel) Allows(required DelegationLevel) bool {
+	return l.IsValid() && delegationRanks[l] >= delegationRanks[required]
+}
+
+// DelegationLevelForMethod возвращает уровень, необходимый для выполнения HTTP-метода от чужого имени:
+// чтение для GET, запись для создания, принятия и отклонения, полное управление для удаления
+func DelegationLevelForMethod(method string) DelegationLevel {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return DelegationRead
+	case http.MethodDelete:
+		return DelegationManage
+	default:
+		return DelegationWrite
+	}
+}
+
+// Delegation разрешение помощнику (Delegate) действовать от имени владельца календаря (Owner)
+type Delegation struct {
+	gorm.Model
+	OwnerID    uint            `json:"owner_id" gorm:"not null;index"`
+	DelegateID uint            `json:"delegate_id" gorm:"not null;index"`
+	Level      DelegationLevel `json:"level" gorm:"type:varchar(16);not null"`
+	// Связи
+	Owner    *User `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+	Delegate *User `json:"-" gorm:"foreignKey:DelegateID;constraint:OnDelete:CASCADE"`
+}
+
+// NewDelegation создает новое разрешение на делегирование
+func NewDelegation(ownerID, delegateID uint, level DelegationLevel) *Delegation {
+	return &Delegation{
+		OwnerID:    ownerID,
+		DelegateID: delegateID,
+		Level:      level,
+	}
+}
+
+// DelegationAudit запись журнала о действии, выполненном помощником от имени владельца
+type DelegationAudit struct {
+	gorm.Model
+	ActorID uint   `json:"actor_id" gorm:"not null;index"`
+	OwnerID uint   `json:"owner_id" gorm:"not null;index"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status"`
+}
@@ -14,23 +14,57 @@ const (
 	StatusBusy     EventStatus = "Занят"
 	StatusDecline  EventStatus = "Отклонено"
 	StatusSent     EventStatus = "Отправлено"
+	// StatusOutOfOffice приглашение отклонено автоматически: пользователь отсутствует
+	StatusOutOfOffice EventStatus = "Нет на месте"
 )
 
 type UserStatus struct {
-	UserId   uint        //для добавления участников в обработчике
-	UserName string      `json:"user_name"`
-	Status   EventStatus `json:"status"`
+	UserId   uint            //для добавления участников в обработчике
+	UserName string          `json:"user_name"`
+	Status   EventStatus     `json:"status"`
+	Role     ParticipantRole `json:"role,omitempty"`
+	GroupID  *uint           `json:"group_id,omitempty"`
+	// Message сообщение об отсутствии при автоматическом отказе
+	Message string `json:"message,omitempty"`
 }
 type Event struct {
 	gorm.Model
-	Title       string    `json:"title" gorm:"not null"`
-	Description string    `json:"description"`
-	StartDate   time.Time `json:"start_date" `
-	Duration    int       `json:"duration_min"`
-	CreatorID   uint      `json:"creator_id" gorm:"not null"`
+	Title       string `json:"title" gorm:"not null"`
+	Description string `json:"description"`
+	// Agenda повестка встречи
+	Agenda string `json:"agenda"`
+	// Minutes протокол встречи, заполняется после нее
+	Minutes   string    `json:"minutes"`
+	StartDate time.Time `json:"start_date" gorm:"index"`
+	Duration  int       `json:"duration_min"`
+	CreatorID uint      `json:"creator_id" gorm:"not null"`
+	// OrganizationID организация создателя события
+	OrganizationID uint `json:"organization_id" gorm:"not null;default:0;index"`
+	// ConferenceLink ссылка для подключения к видеовстрече
+	ConferenceLink string `json:"conference_link"`
+	// CalendarID календарь создателя, к которому относится событие
+	CalendarID *uint `json:"calendar_id" gorm:"index"`
+	// Version растет при каждом изменении события, отдается клиенту как ETag
+	Version int `json:"version" gorm:"not null;default:1"`
+	// SeriesID серия встреч, ID первого события серии
+	SeriesID *uint `json:"series_id,omitempty" gorm:"index"`
+	// Focus блок фокус-времени, поставленный автоматически: для занятости считается мягким
+	Focus bool `json:"focus" gorm:"not null;default:false"`
+	// Tags метки события, загружаются только при выводе списков
+	Tags []Tag `json:"tags,omitempty" gorm:"many2many:event_tags"`
 
 	// Связи
-	Creator *User `gorm:"foreignKey:CreatorID;constraint:OnDelete:CASCADE"` //для API чтобы в некоторых случаях было NULL, а не пустые поля.
+	Creator  *User     `gorm:"foreignKey:CreatorID;constraint:OnDelete:CASCADE"` //для API чтобы в некоторых случаях было NULL, а не пустые поля.
+	Calendar *Calendar `json:"-" gorm:"foreignKey:CalendarID;constraint:OnDelete:SET NULL"`
+}
+
+// EventSearchHit событие, найденное полнотекстовым поиском
+type EventSearchHit struct {
+	Event
+	// Rank релевантность события запросу, выше - точнее
+	Rank float64 `json:"rank"`
+	// Headline фрагменты названия, описания, повестки и протокола с подсвеченными словами запроса
+	Headline string `json:"headline"`
 }
 
 // NewEvent создает новый объект события
@@ -41,6 +75,21 @@ func NewEvent(title, description string, duration int, creatorID uint, startDate
 		StartDate:   startDate,
 		Duration:    duration,
 		CreatorID:   creatorID,
+		Version:     1,
+	}
+}
+
+// FreeBusyView возвращает копию события без подробностей: только время и создатель.
+// Используется, когда календарь открыт пользователю на уровне "только занятость".
+func (e *Event) FreeBusyView() *Event {
+	return &Event{
+		Model:      gorm.Model{ID: e.ID},
+		Title:      string(StatusBusy),
+		StartDate:  e.StartDate,
+		Duration:   e.Duration,
+		CreatorID:  e.CreatorID,
+		CalendarID: e.CalendarID,
+		Focus:      e.Focus,
 	}
 }
 
@@ -50,7 +99,9 @@ type EventRepository interface {
 	FindById(id uint) (*Event, error)
 	FindAllByCreatorId(id uint) ([]Event, error)
 	Update(event *Event) (*Event, error)
+	UpdateIfVersion(event *Event, version int) (*Event, error)
 	DeleteById(id uint) error
-	GetEventWithCreator(eventID, userID uint) (*Event, error)
+	DeleteIfVersion(id uint, version int) error
+	GetEventWithCreator(eventID, userID, organizationID uint) (*Event, error)
 	GetEventsWithCreators() ([]Event, error)
 }
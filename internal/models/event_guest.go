@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventGuest внешний гость события без учетной записи, приглашенный по email.
+// Зарегистрировавшись с тем же email, гость может перенести приглашение в участие пользователя,
+// подтвердив адрес ссылкой из письма.
+type EventGuest struct {
+	gorm.Model
+	EventID uint            `json:"event_id" gorm:"not null;uniqueIndex:idx_event_guests_event_email,where:deleted_at IS NULL"`
+	Email   string          `json:"email" gorm:"not null;uniqueIndex:idx_event_guests_event_email,where:deleted_at IS NULL;index"`
+	Name    string          `json:"name"`
+	Role    ParticipantRole `json:"role" gorm:"type:varchar(32);default:'required'"`
+	Status  EventStatus     `json:"status" gorm:"type:varchar(255);default:'Отправлено'"`
+	// RespondedAt когда гость ответил по ссылке из письма
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	// Связи
+	Event *Event `json:"-" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+}
+
+// NewEventGuest создает приглашение гостя на событие
+func NewEventGuest(eventID uint, email, name string, role ParticipantRole) *EventGuest {
+	return &EventGuest{
+		EventID: eventID,
+		Email:   email,
+		Name:    name,
+		Role:    role,
+		Status:  StatusSent,
+	}
+}
@@ -1,22 +1,62 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Роли участников события
+type ParticipantRole string
+
+const (
+	RoleOrganizer   ParticipantRole = "organizer"
+	RoleCoOrganizer ParticipantRole = "co-organizer"
+	RoleRequired    ParticipantRole = "required"
+	RoleOptional    ParticipantRole = "optional"
+)
+
+// CanManage сообщает, может ли участник с этой ролью редактировать событие и список гостей
+func (r ParticipantRole) CanManage() bool {
+	return r == RoleOrganizer || r == RoleCoOrganizer
+}
+
+// IsValidInviteRole проверяет роль, которую можно назначить приглашенному пользователю
+func (r ParticipantRole) IsValidInviteRole() bool {
+	return r == RoleCoOrganizer || r == RoleRequired || r == RoleOptional
+}
 
 // EventParticipantRepository определяет интерфейс для работы с участниками событий
 type EventParticipantRepository interface {
 	AddParticipant(eventID, userID uint) error
+	AddParticipantWithRole(eventID, userID uint, role ParticipantRole, status EventStatus) error
 	RemoveParticipant(eventID, userID uint) error
 	GetEventParticipants(eventID uint) ([]User, error)
-	GetUserEvents(userID uint) ([]Event, error)
+	GetUserEvents(userID, organizationID uint) ([]Event, error)
 	IsParticipant(eventID, userID uint) (bool, error)
+	IsParticipantInOrganization(eventID, userID, organizationID uint) (bool, error)
+	CanManageEvent(eventID, userID uint) (bool, error)
 }
 
 // EventParticipant представляет связь "многие ко многим" между событиями и пользователями через внешний ключ
 type EventParticipant struct {
 	gorm.Model
-	EventID uint        `json:"event_id" gorm:"not null"`
-	UserID  uint        `json:"user_id" gorm:"not null"`
-	Status  EventStatus `json:"status" gorm:"type:varchar(255);default:'Принято'"`
+	EventID uint            `json:"event_id" gorm:"not null;index"`
+	UserID  uint            `json:"user_id" gorm:"not null;index:idx_event_participants_user_status"`
+	Status  EventStatus     `json:"status" gorm:"type:varchar(255);default:'Принято';index:idx_event_participants_user_status"`
+	Role    ParticipantRole `json:"role" gorm:"type:varchar(32);default:'required'"`
+	// StatusMessage пояснение к статусу, например сообщение об отсутствии
+	StatusMessage string `json:"status_message,omitempty"`
+	// GroupID группа, через которую пользователь был приглашен
+	GroupID *uint `json:"group_id,omitempty" gorm:"index"`
+	// RespondedAt когда участник принял или отклонил приглашение, сбрасывается при переносе события
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	// CheckedInAt когда участник сам отметился на встрече
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	// Attended присутствие на встрече: отметка участника или организатора, nil - не отмечено
+	Attended *bool `json:"attended,omitempty"`
+	// RemindedFor начало встречи, о котором участнику уже напомнили; после переноса напоминание придет снова
+	RemindedFor *time.Time `json:"-"`
 	// Связи
 	Event *Event `gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
 	User  *User  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
@@ -27,5 +67,20 @@ func NewEventParticipant(eventID, userID uint) *EventParticipant {
 	return &EventParticipant{
 		EventID: eventID,
 		UserID:  userID,
+		Role:    RoleRequired,
 	}
 }
+
+// AttendanceRow участник события вместе с данными события для выгрузки
+type AttendanceRow struct {
+	EventID          uint            `json:"event_id"`
+	Title            string          `json:"title"`
+	StartDate        time.Time       `json:"start_date"`
+	Duration         int             `json:"duration_min"`
+	Organizer        string          `json:"organizer"`
+	Participant      string          `json:"participant"`
+	ParticipantEmail string          `json:"participant_email"`
+	Role             ParticipantRole `json:"role"`
+	Status           EventStatus     `json:"status"`
+	RespondedAt      *time.Time      `json:"responded_at"`
+}
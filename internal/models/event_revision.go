@@ -0,0 +1,121 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Действия, которые попадают в историю события
+type RevisionAction string
+
+const (
+	RevisionCreated            RevisionAction = "created"
+	RevisionUpdated            RevisionAction = "updated"
+	RevisionDeleted            RevisionAction = "deleted"
+	RevisionParticipantAdded   RevisionAction = "participant_added"
+	RevisionParticipantRemoved RevisionAction = "participant_removed"
+	RevisionParticipantStatus  RevisionAction = "participant_status"
+)
+
+// FieldChange значение поля до и после изменения
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// FieldChanges изменения по именам полей, хранятся в БД как JSON
+type FieldChanges map[string]FieldChange
+
+// Value сериализует изменения в JSON для записи в БД
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan читает изменения из JSON-колонки
+func (c *FieldChanges) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = FieldChanges{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for FieldChanges")
+	}
+	return json.Unmarshal(data, c)
+}
+
+// EventRevision версия события: кто, когда и что изменил
+type EventRevision struct {
+	gorm.Model
+	EventID uint           `json:"event_id" gorm:"not null;uniqueIndex:idx_event_revision"`
+	Version int            `json:"version" gorm:"not null;uniqueIndex:idx_event_revision"`
+	Action  RevisionAction `json:"action" gorm:"type:varchar(32);not null"`
+	// ActorID пользователь, который выполнил изменение, в том числе помощник при делегировании
+	ActorID uint `json:"actor_id" gorm:"not null"`
+	// OnBehalfOfID владелец календаря, если изменение выполнено от его имени
+	OnBehalfOfID *uint        `json:"on_behalf_of_id,omitempty"`
+	Changes      FieldChanges `json:"changes" gorm:"type:jsonb"`
+}
+
+// DiffEvents сравнивает редактируемые поля события и возвращает изменившиеся
+func DiffEvents(before, after *Event) FieldChanges {
+	changes := FieldChanges{}
+	if before.Title != after.Title {
+		changes["title"] = FieldChange{From: before.Title, To: after.Title}
+	}
+	if before.Description != after.Description {
+		changes["description"] = FieldChange{From: before.Description, To: after.Description}
+	}
+	if before.Agenda != after.Agenda {
+		changes["agenda"] = FieldChange{From: before.Agenda, To: after.Agenda}
+	}
+	if before.Minutes != after.Minutes {
+		changes["minutes"] = FieldChange{From: before.Minutes, To: after.Minutes}
+	}
+	if !before.StartDate.Equal(after.StartDate) {
+		changes["start_date"] = FieldChange{From: before.StartDate.Format(time.RFC3339), To: after.StartDate.Format(time.RFC3339)}
+	}
+	if before.Duration != after.Duration {
+		changes["duration"] = FieldChange{From: before.Duration, To: after.Duration}
+	}
+	if before.ConferenceLink != after.ConferenceLink {
+		changes["conference_link"] = FieldChange{From: before.ConferenceLink, To: after.ConferenceLink}
+	}
+	if !equalIDs(before.CalendarID, after.CalendarID) {
+		changes["calendar_id"] = FieldChange{From: before.CalendarID, To: after.CalendarID}
+	}
+	return changes
+}
+
+// ParticipantField имя поля участника в истории, например participants.5.status
+func ParticipantField(userID uint, field string) string {
+	return "participants." + strconv.FormatUint(uint64(userID), 10) + "." + field
+}
+
+// EventSnapshot возвращает значения полей нового события для первой версии истории
+func EventSnapshot(e *Event) FieldChanges {
+	return DiffEvents(&Event{}, e)
+}
+
+func equalIDs(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FeedbackSurvey опрос участника после встречи: создается при отправке, заполняется ответом
+type FeedbackSurvey struct {
+	gorm.Model
+	EventID uint      `json:"event_id" gorm:"not null;uniqueIndex:idx_feedback_event_user"`
+	UserID  uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_feedback_event_user"`
+	SentAt  time.Time `json:"sent_at" gorm:"not null"`
+	// Rating оценка встречи от 1 до 5
+	Rating *int `json:"rating,omitempty"`
+	// Needed была ли встреча нужна
+	Needed      *bool      `json:"needed,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+	// Связи
+	Event *Event `json:"-" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	User  *User  `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// SurveyRecipient участник прошедшей встречи, которому еще не отправлен опрос
+type SurveyRecipient struct {
+	EventID uint   `json:"event_id"`
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Title   string `json:"title"`
+	// OrganizationID организация встречи
+	OrganizationID uint `json:"organization_id"`
+	// Locale и Timezone настройки писем участника
+	Locale   string `json:"locale"`
+	Timezone string `json:"timezone"`
+}
+
+// FeedbackSummary сводка ответов на опрос по событию
+type FeedbackSummary struct {
+	EventID   uint    `json:"event_id"`
+	Sent      int     `json:"sent"`
+	Responses int     `json:"responses"`
+	AvgRating float64 `json:"avg_rating"`
+	// NeededShare доля ответивших, что встреча была нужна
+	NeededShare float64 `json:"needed_share"`
+}
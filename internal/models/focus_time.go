@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BusyLevel занятость пользователя в интервале
+type BusyLevel int
+
+const (
+	BusyFree BusyLevel = iota
+	// BusySoft пересекается только с блоками фокус-времени, которые переносятся автоматически
+	BusySoft
+	BusyHard
+)
+
+// FocusTimeGoal недельная цель фокус-времени пользователя
+type FocusTimeGoal struct {
+	gorm.Model
+	UserID        uint `json:"user_id" gorm:"not null;uniqueIndex"`
+	WeeklyMinutes int  `json:"weekly_minutes" gorm:"not null"`
+	// MinBlockMinutes блоки короче этого не ставятся
+	MinBlockMinutes int `json:"min_block_minutes" gorm:"not null;default:60"`
+	// Связи
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// Рабочие часы будних дней, по ним ставится фокус-время и считается нагрузка
+const (
+	WorkdayStartHour = 9
+	WorkdayEndHour   = 17
+)
+
+// TimeSlot интервал времени [Start, End)
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Minutes длительность интервала в минутах
+func (s TimeSlot) Minutes() int {
+	return int(s.End.Sub(s.Start) / time.Minute)
+}
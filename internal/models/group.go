@@ -0,0 +1,50 @@
+package models
+
+import "gorm.io/gorm"
+
+// Group команда или отдел, который можно пригласить на событие целиком
+type Group struct {
+	gorm.Model
+	Name        string `json:"name" gorm:"not null"`
+	Description string `json:"description"`
+	OwnerID     uint   `json:"owner_id" gorm:"not null;index"`
+	// OrganizationID организация, в которой создана группа
+	OrganizationID uint          `json:"organization_id" gorm:"not null;default:0;index"`
+	Members        []GroupMember `json:"members,omitempty" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+	// Связи
+	Owner *User `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+}
+
+// GroupMember участник группы, менеджеры могут менять состав группы
+type GroupMember struct {
+	gorm.Model
+	GroupID   uint `json:"group_id" gorm:"not null;uniqueIndex:idx_group_member"`
+	UserID    uint `json:"user_id" gorm:"not null;uniqueIndex:idx_group_member"`
+	IsManager bool `json:"is_manager" gorm:"not null"`
+	// Связи
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// EventGroup связь приглашения с группой: по ней новые участники группы
+// добавляются в будущие события, если это разрешено при приглашении
+type EventGroup struct {
+	gorm.Model
+	EventID           uint            `json:"event_id" gorm:"not null;index"`
+	GroupID           uint            `json:"group_id" gorm:"not null;index"`
+	Role              ParticipantRole `json:"role" gorm:"type:varchar(32);not null"`
+	IncludeNewMembers bool            `json:"include_new_members" gorm:"not null"`
+	// Связи
+	Event *Event `json:"-" gorm:"foreignKey:EventID;constraint:OnDelete:CASCADE"`
+	Group *Group `json:"-" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+}
+
+// NewGroup создает группу, владелец сразу становится ее менеджером
+func NewGroup(ownerID, organizationID uint, name, description string) *Group {
+	return &Group{
+		Name:           name,
+		Description:    description,
+		OwnerID:        ownerID,
+		OrganizationID: organizationID,
+		Members:        []GroupMember{{UserID: ownerID, IsManager: true}},
+	}
+}
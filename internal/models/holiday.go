@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HolidayCalendar производственный календарь организации
+type HolidayCalendar struct {
+	gorm.Model
+	OrganizationID uint      `json:"organization_id" gorm:"not null;default:0;index"`
+	Name           string    `json:"name" gorm:"not null"`
+	Holidays       []Holiday `json:"holidays,omitempty" gorm:"foreignKey:CalendarID;constraint:OnDelete:CASCADE"`
+}
+
+// Holiday нерабочий день календаря
+type Holiday struct {
+	gorm.Model
+	CalendarID uint      `json:"calendar_id" gorm:"not null;index"`
+	Date       time.Time `json:"date" gorm:"type:date;not null;index"`
+	Name       string    `json:"name"`
+}
+
+// HolidayAssignment назначение календаря пользователю или группе, заполнено одно из полей
+type HolidayAssignment struct {
+	gorm.Model
+	CalendarID uint  `json:"calendar_id" gorm:"not null;index"`
+	UserID     *uint `json:"user_id,omitempty" gorm:"index"`
+	GroupID    *uint `json:"group_id,omitempty" gorm:"index"`
+	// Связи
+	Calendar *HolidayCalendar `json:"-" gorm:"foreignKey:CalendarID;constraint:OnDelete:CASCADE"`
+	User     *User            `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Group    *Group           `json:"-" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE"`
+}
+
+// UserHoliday праздник, который приходится на конкретного пользователя
+type UserHoliday struct {
+	UserID uint      `json:"user_id"`
+	Date   time.Time `json:"date"`
+	Name   string    `json:"name"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// MeetingSpan принятая пользователем встреча, из которой считается нагрузка
+type MeetingSpan struct {
+	UserID    uint      `json:"user_id"`
+	EventID   uint      `json:"event_id"`
+	StartDate time.Time `json:"start_date"`
+	Duration  int       `json:"duration_min"`
+}
+
+// End возвращает время окончания встречи
+func (s MeetingSpan) End() time.Time {
+	return s.StartDate.Add(time.Duration(s.Duration) * time.Minute)
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification уведомление в личном кабинете пользователя
+type Notification struct {
+	gorm.Model
+	UserID uint   `json:"user_id" gorm:"not null;index:idx_notifications_user_read"`
+	Kind   string `json:"kind" gorm:"type:varchar(32);not null"`
+	Title  string `json:"title" gorm:"not null"`
+	Body   string `json:"body"`
+	// EventID событие, к которому относится уведомление
+	EventID *uint `json:"event_id,omitempty"`
+	// ReadAt когда пользователь прочитал уведомление, nil - не прочитано
+	ReadAt *time.Time `json:"read_at,omitempty" gorm:"index:idx_notifications_user_read"`
+	// Связи
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
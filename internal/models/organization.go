@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultOrganizationID тенант пользователей, которые еще не вступили ни в одну организацию.
+// Это общее пространство, как до появления организаций: его пользователи видят друг друга
+// и могут приглашать друг друга. Для изоляции команда создает собственную организацию.
+const DefaultOrganizationID uint = 0
+
+// Роли пользователя в организации
+type OrgRole string
+
+const (
+	OrgRoleMember OrgRole = "member"
+	OrgRoleAdmin  OrgRole = "admin"
+)
+
+// Organization рабочее пространство компании: пользователи разных организаций не видят друг друга
+type Organization struct {
+	gorm.Model
+	Name string `json:"name" gorm:"not null"`
+}
+
+// OrganizationInvite приглашение вступить в организацию по email
+type OrganizationInvite struct {
+	gorm.Model
+	OrganizationID uint       `json:"organization_id" gorm:"not null;index"`
+	Email          string     `json:"email" gorm:"not null"`
+	Token          string     `json:"-" gorm:"uniqueIndex;not null"`
+	InvitedBy      uint       `json:"invited_by" gorm:"not null"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	// Связи
+	Organization *Organization `json:"-" gorm:"foreignKey:OrganizationID;constraint:OnDelete:CASCADE"`
+}
+
+// IsActive сообщает, можно ли еще принять приглашение
+func (i *OrganizationInvite) IsActive(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
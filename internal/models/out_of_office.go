@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutOfOffice период отсутствия пользователя: приглашения на это время отклоняются автоматически
+type OutOfOffice struct {
+	gorm.Model
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"not null"`
+	EndDate   time.Time `json:"end_date" gorm:"not null"`
+	Message   string    `json:"message"`
+	// Связи
+	User *User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// NewOutOfOffice создает период отсутствия
+func NewOutOfOffice(userID uint, start, end time.Time, message string) *OutOfOffice {
+	return &OutOfOffice{
+		UserID:    userID,
+		StartDate: start,
+		EndDate:   end,
+		Message:   message,
+	}
+}
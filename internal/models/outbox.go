@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxStatus состояние сообщения в outbox
+type OutboxStatus string
+
+const (
+	// OutboxPending ждет отправки или повторной попытки
+	OutboxPending OutboxStatus = "pending"
+	// OutboxSent доставлено
+	OutboxSent OutboxStatus = "sent"
+	// OutboxDead попытки исчерпаны, сообщение ждет разбора администратором
+	OutboxDead OutboxStatus = "dead"
+)
+
+// OutboxMessage уведомление для одного канала доставки. Записывается в той же транзакции,
+// что и изменение, о котором уведомляет, и доставляется фоновым обработчиком.
+type OutboxMessage struct {
+	gorm.Model
+	// OrganizationID организация, в которой произошло изменение, 0 - системные письма
+	OrganizationID uint   `json:"organization_id" gorm:"not null;default:0;index"`
+	Channel        string `json:"channel" gorm:"type:varchar(16);not null"`
+	Kind           string `json:"kind" gorm:"type:varchar(32);not null"`
+	// Recipient адрес получателя для поиска сообщений администратором
+	Recipient string `json:"recipient"`
+	// Payload уведомление целиком; содержит ссылки и коды, поэтому наружу не отдается
+	Payload       string       `json:"-" gorm:"type:text;not null"`
+	Status        OutboxStatus `json:"status" gorm:"type:varchar(16);not null;default:'pending';index:idx_outbox_due,priority:1"`
+	Attempts      int          `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" gorm:"not null;index:idx_outbox_due,priority:2"`
+	LastError     string       `json:"last_error,omitempty"`
+	SentAt        *time.Time   `json:"sent_at,omitempty"`
+}
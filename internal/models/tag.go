@@ -0,0 +1,40 @@
+package models
+
+import "gorm.io/gorm"
+
+// Tag метка для классификации событий: "клиент", "внутреннее", "найм".
+// Метка без владельца принадлежит организации и доступна всем ее участникам.
+type Tag struct {
+	gorm.Model
+	OrganizationID uint   `json:"organization_id" gorm:"not null;default:0;index"`
+	OwnerID        *uint  `json:"owner_id,omitempty" gorm:"index"`
+	Name           string `json:"name" gorm:"not null"`
+	// Color цвет метки в формате #RRGGBB
+	Color string `json:"color" gorm:"type:varchar(7);not null"`
+	// Связи
+	Owner *User `json:"-" gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE"`
+}
+
+// EventTag связь события с меткой
+type EventTag struct {
+	EventID uint `json:"event_id" gorm:"primaryKey"`
+	TagID   uint `json:"tag_id" gorm:"primaryKey;index"`
+}
+
+// NewTag создает метку пользователя, при ownerID == nil метка принадлежит организации
+func NewTag(organizationID uint, ownerID *uint, name, color string) *Tag {
+	return &Tag{
+		OrganizationID: organizationID,
+		OwnerID:        ownerID,
+		Name:           name,
+		Color:          color,
+	}
+}
+
+// UsableBy проверяет, может ли пользователь организации ставить метку на события
+func (t *Tag) UsableBy(userID, organizationID uint) bool {
+	if t.OrganizationID != organizationID {
+		return false
+	}
+	return t.OwnerID == nil || *t.OwnerID == userID
+}
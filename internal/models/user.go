@@ -11,14 +11,23 @@ type User struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Email    string `gorm:"unique index" json:"email"`
+	// OrganizationID организация (тенант) пользователя
+	OrganizationID uint    `json:"organization_id" gorm:"not null;default:0"`
+	OrgRole        OrgRole `json:"org_role" gorm:"type:varchar(16);default:'member'"`
+	// Timezone часовой пояс IANA, в нем показывается время в письмах
+	Timezone string `json:"timezone" gorm:"type:varchar(64);not null;default:'UTC'"`
+	// Locale язык писем: ru или en
+	Locale string `json:"locale" gorm:"type:varchar(8);not null;default:'ru'"`
 }
 
 type UserResponse struct {
-	ID        uint       `json:"id"`
-    CreatedAt time.Time  `json:"created_at"`
-    UpdatedAt time.Time  `json:"updated_at"`
-    Username  string     `json:"username"`
-    Email     string     `json:"email"`
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Timezone  string    `json:"timezone"`
+	Locale    string    `json:"locale"`
 }
 
 func NewUser(email string, password string, name string) *User {
@@ -29,21 +38,35 @@ func NewUser(email string, password string, name string) *User {
 	}
 }
 
+// Location часовой пояс пользователя, UTC если пояс не задан или неизвестен
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
 func ToUserResponse(u *User) *UserResponse {
-    return &UserResponse{
-        ID:        u.ID,
-        CreatedAt: u.CreatedAt,
-        UpdatedAt: u.UpdatedAt,
-        Username:  u.Username,
-        Email:     u.Email,
-    }
+	return &UserResponse{
+		ID:        u.ID,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		Username:  u.Username,
+		Email:     u.Email,
+		Timezone:  u.Timezone,
+		Locale:    u.Locale,
+	}
 }
 
 type UserRepository interface {
 	Create(user *User) (*User, error)
 	FindById(id uint) (*User, error)
 	FindByEmail(email string) (*User, error)
-	FindAllUsers(limit, offset int, search string) ([]UserResponse, int64, error)
+	FindAllUsers(organizationID uint, limit, offset int, search string) ([]UserResponse, int64, error)
 	Update(user *User) (*User, error)
 	DeleteById(id uint) error
 }
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+type NotificationHandler struct {
+	NotificationRepository *NotificationRepository
+	JWTService             *jwt.JWT
+}
+
+type NotificationHandlerDeps struct {
+	NotificationRepository *NotificationRepository
+	JWTService             *jwt.JWT
+}
+
+// NewNotificationHandler регистрирует обработчики ленты уведомлений пользователя
+func NewNotificationHandler(mux *chi.Mux, deps NotificationHandlerDeps) {
+	handler := &NotificationHandler{
+		NotificationRepository: deps.NotificationRepository,
+		JWTService:             deps.JWTService,
+	}
+	mux.Handle("GET /me/notifications", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("PUT /me/notifications/read-all", middleware.IsAuthed(handler.MarkAllRead(), handler.JWTService))
+	mux.Handle("PUT /me/notifications/{id}/read", middleware.IsAuthed(handler.MarkRead(), handler.JWTService))
+}
+
+// List возвращает ленту уведомлений пользователя от новых к старым и число непрочитанных.
+// ?unread=true - только непрочитанные, ?cursor - значение next_cursor с предыдущей страницы.
+func (h *NotificationHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		limit := defaultListLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > maxListLimit {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+		var cursor uint
+		if raw := r.URL.Query().Get("cursor"); raw != "" {
+			parsed, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil || parsed == 0 {
+				http.Error(w, "Invalid cursor", http.StatusBadRequest)
+				return
+			}
+			cursor = uint(parsed)
+		}
+		unreadOnly := r.URL.Query().Get("unread") == "true"
+		//берем на одно уведомление больше, чтобы понять, есть ли следующая страница
+		notifications, err := h.NotificationRepository.FindByUser(userID, unreadOnly, cursor, limit+1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		unread, err := h.NotificationRepository.CountUnread(userID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page := NotificationPage{Items: notifications, UnreadCount: unread}
+		if len(notifications) > limit {
+			page.Items = notifications[:limit]
+			page.NextCursor = strconv.FormatUint(uint64(page.Items[limit-1].ID), 10)
+		}
+		res.JsonResponse(w, page, http.StatusOK)
+	}
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя
+func (h *NotificationHandler) MarkAllRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		marked, err := h.NotificationRepository.MarkAllRead(userID, time.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, MarkAllReadResponse{Marked: marked}, http.StatusOK)
+	}
+}
+
+// MarkRead отмечает уведомление прочитанным
+func (h *NotificationHandler) MarkRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.NotificationRepository.MarkRead(id, userID, time.Now().UTC())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Notification not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Notification marked as read", http.StatusOK)
+	}
+}
@@ -0,0 +1,93 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNotificationHandler(t *testing.T) (*NotificationHandler, sqlmock.Sqlmock) {
+	gormDB, mockDB, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	return &NotificationHandler{NotificationRepository: NewNotificationRepository(&db.Db{DB: gormDB})}, mockDB
+}
+
+func authedRequest(method, target string, userID uint) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(context.WithValue(req.Context(), middleware.ContextUserIDKey, userID))
+}
+
+func TestListNotificationsPaginatesByCursor(t *testing.T) {
+	handler, mockDB := setupNotificationHandler(t)
+	mockDB.ExpectQuery(`SELECT \* FROM "notifications" WHERE user_id = \$1 AND read_at IS NULL AND id < \$2 .* ORDER BY id DESC LIMIT \$3`).
+		WithArgs(uint(3), uint(40), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "kind", "title"}).
+			AddRow(39, 3, "invited", "Invitation: Sync").
+			AddRow(35, 3, "updated", "Event details updated: Sync").
+			AddRow(31, 3, "cancelled", "Cancelled: Retro"))
+	mockDB.ExpectQuery(`SELECT count\(\*\) FROM "notifications" WHERE \(user_id = \$1 AND read_at IS NULL\)`).
+		WithArgs(uint(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	w := httptest.NewRecorder()
+	handler.List()(w, authedRequest(http.MethodGet, "/me/notifications?unread=true&cursor=40&limit=2", 3))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var page NotificationPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Items, 2)
+	require.Equal(t, int64(7), page.UnreadCount)
+	require.Equal(t, "35", page.NextCursor)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestListNotificationsLastPageHasNoCursor(t *testing.T) {
+	handler, mockDB := setupNotificationHandler(t)
+	mockDB.ExpectQuery(`SELECT \* FROM "notifications" WHERE user_id = \$1 .* ORDER BY id DESC LIMIT \$2`).
+		WithArgs(uint(3), 51).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "kind", "title"}).AddRow(2, 3, "reminder", "Reminder: Sync"))
+	mockDB.ExpectQuery(`SELECT count\(\*\) FROM "notifications"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	w := httptest.NewRecorder()
+	handler.List()(w, authedRequest(http.MethodGet, "/me/notifications", 3))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var page NotificationPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Items, 1)
+	require.Empty(t, page.NextCursor)
+}
+
+func TestListNotificationsRejectsBadCursor(t *testing.T) {
+	handler, _ := setupNotificationHandler(t)
+	w := httptest.NewRecorder()
+	handler.List()(w, authedRequest(http.MethodGet, "/me/notifications?cursor=abc", 3))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	handler, mockDB := setupNotificationHandler(t)
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "notifications" SET "read_at"=\$1,"updated_at"=\$2 WHERE \(user_id = \$3 AND read_at IS NULL\)`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), uint(3)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mockDB.ExpectCommit()
+
+	w := httptest.NewRecorder()
+	handler.MarkAllRead()(w, authedRequest(http.MethodPut, "/me/notifications/read-all", 3))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp MarkAllReadResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, int64(4), resp.Marked)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
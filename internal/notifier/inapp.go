@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+)
+
+// InAppChannel сохраняет уведомления в личном кабинете пользователя
+type InAppChannel struct {
+	Repository *NotificationRepository
+}
+
+// NewInAppChannel создает канал уведомлений в личном кабинете
+func NewInAppChannel(repo *NotificationRepository) *InAppChannel {
+	return &InAppChannel{Repository: repo}
+}
+
+func (c *InAppChannel) Name() string { return "inapp" }
+
+// Send сохраняет уведомление без секретов (см. Notification.Redacted).
+// У гостей без учетной записи личного кабинета нет.
+func (c *InAppChannel) Send(_ context.Context, n Notification) error {
+	if n.Recipient.UserID == 0 {
+		return nil
+	}
+	n = n.Redacted()
+	notification := &models.Notification{
+		UserID: n.Recipient.UserID,
+		Kind:   string(n.Kind),
+		Title:  n.Subject,
+		Body:   n.Text,
+	}
+	if n.EventID != 0 {
+		eventID := n.EventID
+		notification.EventID = &eventID
+	}
+	return c.Repository.Create(notification)
+}
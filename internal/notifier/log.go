@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+)
+
+// LogChannel канал для разработки: уведомления пишутся в файл строками JSON
+// или, если файл не задан, в журнал приложения
+type LogChannel struct {
+	Writer io.Writer
+	Logger logger.LoggerInterface
+	mu     sync.Mutex
+}
+
+// NewLogChannel открывает файл для дозаписи. Без пути уведомления попадают в журнал.
+func NewLogChannel(path string, log logger.LoggerInterface) (*LogChannel, error) {
+	if path == "" {
+		return &LogChannel{Logger: log}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &LogChannel{Writer: file}, nil
+}
+
+func (c *LogChannel) Name() string { return "log" }
+
+// Send записывает уведомление без секретов (см. Notification.Redacted)
+func (c *LogChannel) Send(_ context.Context, n Notification) error {
+	n = n.Redacted()
+	if c.Writer == nil {
+		c.Logger.Info("Notification", "kind", n.Kind, "recipient", n.Recipient.Email, "subject", n.Subject, "text", n.Text)
+		return nil
+	}
+	line, err := json.Marshal(struct {
+		SentAt time.Time `json:"sent_at"`
+		Notification
+	}{time.Now().UTC(), n})
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.Writer.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+)
+
+// Kind тип уведомления, по нему выбирается шаблон письма
+type Kind string
+
+const (
+	KindInvited            Kind = "invited"
+	KindUpdated            Kind = "updated"
+	KindCancelled          Kind = "cancelled"
+	KindReminder           Kind = "reminder"
+	KindPasswordReset      Kind = "password_reset"
+	KindOrganizationInvite Kind = "organization_invite"
+	KindFeedbackSurvey     Kind = "feedback_survey"
+	KindMentioned          Kind = "mentioned"
+)
+
+// Kinds все типы уведомлений, для каждого нужен шаблон на каждом языке
+var Kinds = []Kind{
+	KindInvited,
+	KindUpdated,
+	KindCancelled,
+	KindReminder,
+	KindPasswordReset,
+	KindOrganizationInvite,
+	KindFeedbackSurvey,
+	KindMentioned,
+}
+
+// Recipient получатель уведомления. UserID пустой у внешних гостей и еще не зарегистрированных адресатов.
+type Recipient struct {
+	UserID uint   `json:"user_id,omitempty"`
+	Email  string `json:"email"`
+	Name   string `json:"name,omitempty"`
+	// Locale язык письма, пустой - язык по умолчанию
+	Locale string `json:"locale,omitempty"`
+	// Timezone часовой пояс IANA для времени в письме, пустой - UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// UserRecipient получатель-пользователь с его языком и часовым поясом
+func UserRecipient(u *models.User) Recipient {
+	return Recipient{UserID: u.ID, Email: u.Email, Name: u.Username, Locale: u.Locale, Timezone: u.Timezone}
+}
+
+// EventDetails данные события для шаблонов
+type EventDetails struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description,omitempty"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Organizer      string    `json:"organizer,omitempty"`
+	ConferenceLink string    `json:"conference_link,omitempty"`
+}
+
+// NewEventDetails собирает данные события. Организатор может быть неизвестен.
+func NewEventDetails(ev *models.Event, organizer *models.User) EventDetails {
+	details := EventDetails{
+		ID:             ev.ID,
+		Title:          ev.Title,
+		Description:    ev.Description,
+		Start:          ev.StartDate,
+		End:            ev.StartDate.Add(time.Duration(ev.Duration) * time.Minute),
+		ConferenceLink: ev.ConferenceLink,
+	}
+	if organizer != nil {
+		details.Organizer = organizer.Username
+		if details.Organizer == "" {
+			details.Organizer = organizer.Email
+		}
+	}
+	return details
+}
+
+// Attachment вложение уведомления, доставляется только по почте
+type Attachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"-"`
+}
+
+// Notification уведомление, которое каналы доставляют каждый по-своему.
+// Subject, Text и HTML заполняются из шаблонов перед отправкой.
+type Notification struct {
+	Kind      Kind      `json:"kind"`
+	Recipient Recipient `json:"recipient"`
+	// EventID событие, к которому относится уведомление
+	EventID uint          `json:"event_id,omitempty"`
+	Event   *EventDetails `json:"event,omitempty"`
+	// Organization название организации в приглашении вступить в нее
+	Organization string `json:"organization,omitempty"`
+	// Token одноразовый код, который получатель вводит вручную
+	Token   string `json:"-"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"-"`
+	// Links ссылки для действий получателя: accept, decline, reset и т.п.
+	Links       map[string]string `json:"links,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+}
+
+// Redacted копия уведомления для каналов кроме почты. У сброса пароля и приглашения в организацию
+// убираются ссылки, код и текст письма, в который они подставлены: за пределами почты такие
+// уведомления доходят только как факт отправки.
+func (n Notification) Redacted() Notification {
+	if n.Kind != KindPasswordReset && n.Kind != KindOrganizationInvite {
+		return n
+	}
+	n.Links = nil
+	n.Token = ""
+	n.Text = ""
+	n.HTML = ""
+	return n
+}
+
+func eventNotification(kind Kind, to Recipient, ev EventDetails) Notification {
+	return Notification{Kind: kind, Recipient: to, EventID: ev.ID, Event: &ev}
+}
+
+// Invited приглашение участника со ссылками для ответа одним кликом
+func Invited(to Recipient, ev EventDetails, accept, decline string) Notification {
+	n := eventNotification(KindInvited, to, ev)
+	n.Links = map[string]string{"accept": accept, "decline": decline}
+	return n
+}
+
+// GuestInvited приглашение внешнего гостя: ответ без учетной записи и приглашение .ics
+func GuestInvited(to Recipient, ev EventDetails, accept, decline string, invite []byte) Notification {
+	n := Invited(to, ev, accept, decline)
+	n.Attachments = []Attachment{{Name: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Data: invite}}
+	return n
+}
+
+// Updated изменение описания события без переноса по времени
+func Updated(to Recipient, ev EventDetails) Notification {
+	return eventNotification(KindUpdated, to, ev)
+}
+
+// Mentioned упоминание участника в описании, повестке или протоколе события
+func Mentioned(to Recipient, ev EventDetails) Notification {
+	return eventNotification(KindMentioned, to, ev)
+}
+
+// Cancelled отмена события
+func Cancelled(to Recipient, ev EventDetails) Notification {
+	return eventNotification(KindCancelled, to, ev)
+}
+
+// Reminder напоминание о скором начале встречи
+func Reminder(to Recipient, ev EventDetails) Notification {
+	n := eventNotification(KindReminder, to, ev)
+	if ev.ConferenceLink != "" {
+		n.Links = map[string]string{"join": ev.ConferenceLink}
+	}
+	return n
+}
+
+// PasswordReset ссылка для сброса пароля
+func PasswordReset(to Recipient, link string) Notification {
+	return Notification{
+		Kind:      KindPasswordReset,
+		Recipient: to,
+		Links:     map[string]string{"reset": link},
+	}
+}
+
+// OrganizationInvite приглашение вступить в организацию
+func OrganizationInvite(to Recipient, organization, token string) Notification {
+	return Notification{
+		Kind:         KindOrganizationInvite,
+		Recipient:    to,
+		Organization: organization,
+		Token:        token,
+	}
+}
+
+// FeedbackSurvey опрос после встречи
+func FeedbackSurvey(to Recipient, ev EventDetails, link string) Notification {
+	n := eventNotification(KindFeedbackSurvey, to, ev)
+	n.Links = map[string]string{"survey": link}
+	return n
+}
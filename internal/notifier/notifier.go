@@ -0,0 +1,112 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+)
+
+// Notifier принимает уведомления от обработчиков
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Channel способ доставки уведомлений
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Dispatcher заполняет уведомление из шаблонов и доставляет во все подключенные каналы.
+// Сбой одного канала не мешает остальным: ошибки записываются в журнал и возвращаются вместе.
+type Dispatcher struct {
+	Channels []Channel
+	Renderer *Renderer
+	Logger   logger.LoggerInterface
+}
+
+// NewDispatcher создает диспетчер со встроенными шаблонами и указанными каналами
+func NewDispatcher(log logger.LoggerInterface, channels ...Channel) *Dispatcher {
+	return &Dispatcher{Channels: channels, Renderer: DefaultRenderer(), Logger: log}
+}
+
+// NewFromConfig подключает каналы, перечисленные в конфигурации
+func NewFromConfig(cfg *configs.Config, repo *NotificationRepository, log logger.LoggerInterface) (*Dispatcher, error) {
+	dispatcher := NewDispatcher(log)
+	if cfg.Notifications.TemplatesDir != "" {
+		renderer, err := NewRenderer(cfg.Notifications.TemplatesDir)
+		if err != nil {
+			return nil, err
+		}
+		dispatcher.Renderer = renderer
+	}
+	for _, name := range cfg.Notifications.Channels {
+		var channel Channel
+		switch strings.TrimSpace(name) {
+		case "":
+			continue
+		case "smtp":
+			channel = NewSMTPChannel(cfg)
+		case "webhook":
+			if cfg.Notifications.WebhookURL == "" {
+				return nil, errors.New("webhook channel requires NOTIFY_WEBHOOK_URL")
+			}
+			channel = NewWebhookChannel(cfg.Notifications.WebhookURL, cfg.Notifications.WebhookSecret)
+		case "inapp":
+			channel = NewInAppChannel(repo)
+		case "log":
+			logChannel, err := NewLogChannel(cfg.Notifications.LogFile, log)
+			if err != nil {
+				return nil, err
+			}
+			channel = logChannel
+		default:
+			return nil, fmt.Errorf("unknown notification channel %q", name)
+		}
+		dispatcher.Channels = append(dispatcher.Channels, channel)
+	}
+	return dispatcher, nil
+}
+
+// Notify отправляет уведомление во все каналы
+func (d *Dispatcher) Notify(ctx context.Context, n Notification) error {
+	if err := d.Renderer.Render(&n); err != nil {
+		d.Logger.Error("Notification rendering failed", "kind", n.Kind, "error", err)
+		return err
+	}
+	var errs []error
+	for _, channel := range d.Channels {
+		if err := channel.Send(ctx, n); err != nil {
+			d.Logger.Error("Notification delivery failed", "channel", channel.Name(), "kind", n.Kind, "recipient", n.Recipient.Email, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ChannelNames имена подключенных каналов
+func (d *Dispatcher) ChannelNames() []string {
+	names := make([]string, 0, len(d.Channels))
+	for _, channel := range d.Channels {
+		names = append(names, channel.Name())
+	}
+	return names
+}
+
+// Deliver заполняет уведомление из шаблонов и отправляет в один канал
+func (d *Dispatcher) Deliver(ctx context.Context, channelName string, n Notification) error {
+	for _, channel := range d.Channels {
+		if channel.Name() != channelName {
+			continue
+		}
+		if err := d.Renderer.Render(&n); err != nil {
+			return err
+		}
+		return channel.Send(ctx, n)
+	}
+	return fmt.Errorf("notification channel %q is not enabled", channelName)
+}
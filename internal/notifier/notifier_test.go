@@ -0,0 +1,149 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type stubChannel struct {
+	name string
+	err  error
+	sent []Notification
+}
+
+func (c *stubChannel) Name() string { return c.name }
+
+func (c *stubChannel) Send(_ context.Context, n Notification) error {
+	c.sent = append(c.sent, n)
+	return c.err
+}
+
+func testLogger() *logger.Logger {
+	return logger.NewLogger(&configs.Config{})
+}
+
+func TestDispatcherDeliversToAllChannels(t *testing.T) {
+	failing := &stubChannel{name: "failing", err: errors.New("smtp is down")}
+	working := &stubChannel{name: "working"}
+	dispatcher := NewDispatcher(testLogger(), failing, working)
+
+	ev := &models.Event{Title: "Planning"}
+	ev.ID = 7
+	err := dispatcher.Notify(context.Background(), Cancelled(Recipient{UserID: 3, Email: "a@example.com"}, NewEventDetails(ev, nil)))
+
+	require.ErrorContains(t, err, "failing: smtp is down")
+	require.Len(t, failing.sent, 1)
+	require.Len(t, working.sent, 1)
+	require.Equal(t, KindCancelled, working.sent[0].Kind)
+	require.Equal(t, uint(7), working.sent[0].EventID)
+	require.Equal(t, "Отменено: Planning", working.sent[0].Subject)
+}
+
+func TestNewFromConfigRejectsUnknownChannel(t *testing.T) {
+	cfg := &configs.Config{Notifications: configs.NotificationsConfig{Channels: []string{"smtp", "pigeon"}}}
+	_, err := NewFromConfig(cfg, nil, testLogger())
+	require.Error(t, err)
+
+	cfg.Notifications.Channels = []string{"webhook"}
+	_, err = NewFromConfig(cfg, nil, testLogger())
+	require.Error(t, err)
+}
+
+func TestWebhookChannelSignsBody(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(server.URL, "secret")
+	err := channel.Send(context.Background(), Invited(Recipient{Email: "a@example.com"}, EventDetails{ID: 7}, "http://localhost/accept", "http://localhost/decline"))
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	var payload Notification
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Equal(t, KindInvited, payload.Kind)
+	require.Equal(t, "http://localhost/accept", payload.Links["accept"])
+}
+
+func TestWebhookChannelRedactsSecrets(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	reset := PasswordReset(Recipient{Email: "a@example.com"}, "http://localhost/reset?token=abc")
+	reset.Text = "Сбросить пароль: http://localhost/reset?token=abc"
+	require.NoError(t, NewWebhookChannel(server.URL, "").Send(context.Background(), reset))
+	require.NotContains(t, string(body), "token=abc")
+
+	var payload Notification
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Equal(t, KindPasswordReset, payload.Kind)
+	require.Equal(t, "a@example.com", payload.Recipient.Email)
+}
+
+func TestWebhookChannelFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookChannel(server.URL, "").Send(context.Background(), Notification{Kind: KindReminder})
+	require.Error(t, err)
+}
+
+func TestInAppChannelSkipsGuests(t *testing.T) {
+	// репозиторий не задан: обращение к базе привело бы к панике
+	channel := NewInAppChannel(nil)
+	err := channel.Send(context.Background(), Notification{Kind: KindInvited, Recipient: Recipient{Email: "guest@example.com"}})
+	require.NoError(t, err)
+}
+
+func TestLogChannelWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	channel := &LogChannel{Writer: &buf}
+	require.NoError(t, channel.Send(context.Background(), Notification{Kind: KindUpdated, Subject: "first"}))
+	require.NoError(t, channel.Send(context.Background(), Notification{Kind: KindReminder, Subject: "second"}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	require.Equal(t, "reminder", entry["kind"])
+	require.Equal(t, "second", entry["subject"])
+	require.NotEmpty(t, entry["sent_at"])
+}
+
+func TestLogChannelRedactsOrganizationInvite(t *testing.T) {
+	var buf bytes.Buffer
+	channel := &LogChannel{Writer: &buf}
+	invite := OrganizationInvite(Recipient{Email: "a@example.com"}, "Acme", "invite-code")
+	invite.Text = "Код приглашения: invite-code"
+	require.NoError(t, channel.Send(context.Background(), invite))
+	require.NotContains(t, buf.String(), "invite-code")
+	require.Contains(t, buf.String(), "organization_invite")
+}
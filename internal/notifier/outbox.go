@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+)
+
+// Transactional уведомитель, который умеет записывать уведомления в транзакции изменения
+type Transactional interface {
+	WithTx(tx *db.Db) Notifier
+}
+
+// Outbox не отправляет уведомления сам, а записывает по сообщению на каждый канал.
+// Доставляет их OutboxWorker, поэтому медленный или недоступный канал не задерживает запрос.
+type Outbox struct {
+	Repository *OutboxRepository
+	Channels   []string
+}
+
+// NewOutbox создает outbox для указанных каналов
+func NewOutbox(repo *OutboxRepository, channels []string) *Outbox {
+	return &Outbox{Repository: repo, Channels: channels}
+}
+
+// WithTx возвращает outbox, пишущий в транзакцию tx
+func (o *Outbox) WithTx(tx *db.Db) Notifier {
+	return &Outbox{Repository: NewOutboxRepository(tx), Channels: o.Channels}
+}
+
+// Notify записывает уведомление в outbox. Организация берется из контекста запроса.
+func (o *Outbox) Notify(ctx context.Context, n Notification) error {
+	payload, err := encodePayload(n)
+	if err != nil {
+		return err
+	}
+	orgID, _ := middleware.OrganizationID(ctx)
+	now := time.Now().UTC()
+	messages := make([]models.OutboxMessage, 0, len(o.Channels))
+	for _, channel := range o.Channels {
+		messages = append(messages, models.OutboxMessage{
+			OrganizationID: orgID,
+			Channel:        channel,
+			Kind:           string(n.Kind),
+			Recipient:      n.Recipient.Email,
+			Payload:        payload,
+			Status:         models.OutboxPending,
+			NextAttemptAt:  now,
+		})
+	}
+	return o.Repository.Enqueue(messages)
+}
+
+// outboxPayload уведомление для хранения в outbox. В отличие от JSON для вебхука
+// сохраняет все поля, включая одноразовый код и содержимое вложений.
+type outboxPayload struct {
+	Notification
+	Token       string             `json:"token,omitempty"`
+	Attachments []outboxAttachment `json:"attachments,omitempty"`
+}
+
+type outboxAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+func encodePayload(n Notification) (string, error) {
+	payload := outboxPayload{Notification: n, Token: n.Token}
+	for _, a := range n.Attachments {
+		payload.Attachments = append(payload.Attachments, outboxAttachment{Name: a.Name, ContentType: a.ContentType, Data: a.Data})
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func decodePayload(data string) (Notification, error) {
+	var payload outboxPayload
+	if err := json.Unmarshal([]byte(data), &payload); err != nil {
+		return Notification{}, err
+	}
+	n := payload.Notification
+	n.Token = payload.Token
+	n.Attachments = nil
+	for _, a := range payload.Attachments {
+		n.Attachments = append(n.Attachments, Attachment{Name: a.Name, ContentType: a.ContentType, Data: a.Data})
+	}
+	return n, nil
+}
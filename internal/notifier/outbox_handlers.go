@@ -0,0 +1,133 @@
+package notifier
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type OutboxHandler struct {
+	OutboxRepository *OutboxRepository
+	UserRepository   *user.UserRepository
+	JWTService       *jwt.JWT
+}
+
+type OutboxHandlerDeps struct {
+	OutboxRepository *OutboxRepository
+	UserRepository   *user.UserRepository
+	JWTService       *jwt.JWT
+}
+
+// OutboxPage страница сообщений outbox
+type OutboxPage struct {
+	Items  []models.OutboxMessage `json:"items"`
+	Total  int64                  `json:"total"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+// NewOutboxHandler регистрирует обработчики для разбора сообщений outbox администратором организации
+func NewOutboxHandler(mux *chi.Mux, deps OutboxHandlerDeps) {
+	handler := &OutboxHandler{
+		OutboxRepository: deps.OutboxRepository,
+		UserRepository:   deps.UserRepository,
+		JWTService:       deps.JWTService,
+	}
+	mux.Handle("GET /admin/outbox", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("POST /admin/outbox/{id}/requeue", middleware.IsAuthed(handler.Requeue(), handler.JWTService))
+}
+
+// List возвращает сообщения организации, ?status=dead - только недоставленные
+func (h *OutboxHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := h.currentAdmin(w, r)
+		if !ok {
+			return
+		}
+		status := models.OutboxStatus(r.URL.Query().Get("status"))
+		switch status {
+		case "", models.OutboxPending, models.OutboxSent, models.OutboxDead:
+		default:
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+		limit := defaultListLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > maxListLimit {
+				http.Error(w, "Invalid limit", http.StatusBadRequest)
+				return
+			}
+			limit = parsed
+		}
+		offset := 0
+		if raw := r.URL.Query().Get("offset"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 0 {
+				http.Error(w, "Invalid offset", http.StatusBadRequest)
+				return
+			}
+			offset = parsed
+		}
+		messages, total, err := h.OutboxRepository.FindByOrganization(admin.OrganizationID, status, limit, offset)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, OutboxPage{Items: messages, Total: total, Limit: limit, Offset: offset}, http.StatusOK)
+	}
+}
+
+// Requeue возвращает недоставленное сообщение в очередь
+func (h *OutboxHandler) Requeue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		admin, ok := h.currentAdmin(w, r)
+		if !ok {
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = h.OutboxRepository.Requeue(id, admin.OrganizationID, time.Now().UTC())
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Failed message not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Message requeued", http.StatusOK)
+	}
+}
+
+// currentAdmin загружает авторизованного пользователя и проверяет, что он администратор организации
+func (h *OutboxHandler) currentAdmin(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	foundUser, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return nil, false
+	}
+	if foundUser.OrganizationID == models.DefaultOrganizationID || foundUser.OrgRole != models.OrgRoleAdmin {
+		http.Error(w, "Only organization admins can manage the outbox", http.StatusForbidden)
+		return nil, false
+	}
+	return foundUser, true
+}
@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	DataBase *db.Db
+}
+
+// NewOutboxRepository создает новый репозиторий outbox
+func NewOutboxRepository(dataBase *db.Db) *OutboxRepository {
+	return &OutboxRepository{DataBase: dataBase}
+}
+
+// Enqueue сохраняет сообщения. В транзакции изменения сообщения появятся только после Commit.
+func (repo *OutboxRepository) Enqueue(messages []models.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	return repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(&messages).Error
+}
+
+// Claim забирает сообщения, которым пора уходить, и откладывает их на lease,
+// чтобы другой экземпляр обработчика не отправил их повторно. Если обработчик
+// упадет, не завершив отправку, сообщения снова станут доступны после lease.
+func (repo *OutboxRepository) Claim(now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	err := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+		}
+		return tx.Model(&models.OutboxMessage{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkSent отмечает сообщение доставленным
+func (repo *OutboxRepository) MarkSent(id uint, attempts int, at time.Time) error {
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.OutboxSent,
+			"attempts":   attempts,
+			"sent_at":    at,
+			"last_error": "",
+		}).Error
+}
+
+// MarkFailed записывает неудачную попытку: сообщение ждет следующей попытки в next или, если dead, уходит в dead-letter
+func (repo *OutboxRepository) MarkFailed(id uint, attempts int, next time.Time, lastError string, dead bool) error {
+	status := models.OutboxPending
+	if dead {
+		status = models.OutboxDead
+	}
+	return repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"attempts":        attempts,
+			"next_attempt_at": next,
+			"last_error":      lastError,
+		}).Error
+}
+
+// FindByOrganization возвращает сообщения организации, новые первыми, и их общее число
+func (repo *OutboxRepository) FindByOrganization(organizationID uint, status models.OutboxStatus, limit, offset int) ([]models.OutboxMessage, int64, error) {
+	query := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.OutboxMessage{}).
+		Where("organization_id = ?", organizationID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var messages []models.OutboxMessage
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// Requeue возвращает сообщение из dead-letter в очередь с полным числом попыток
+func (repo *OutboxRepository) Requeue(id, organizationID uint, now time.Time) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.OutboxMessage{}).
+		Where("id = ? AND organization_id = ? AND status = ?", id, organizationID, models.OutboxDead).
+		Updates(map[string]interface{}{
+			"status":          models.OutboxPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
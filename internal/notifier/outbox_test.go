@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupOutbox(t *testing.T) (*OutboxRepository, sqlmock.Sqlmock) {
+	gormDB, mockDB, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	return NewOutboxRepository(&db.Db{DB: gormDB}), mockDB
+}
+
+func TestOutboxEnqueuesMessagePerChannel(t *testing.T) {
+	repo, mockDB := setupOutbox(t)
+	outbox := NewOutbox(repo, []string{"smtp", "inapp"})
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`INSERT INTO "outbox_messages" .* VALUES \(.+\),\(.+\) RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mockDB.ExpectCommit()
+
+	ctx := context.WithValue(context.Background(), middleware.ContextOrgIDKey, uint(5))
+	err := outbox.Notify(ctx, PasswordReset(Recipient{UserID: 3, Email: "a@example.com"}, "http://localhost/reset"))
+	require.NoError(t, err)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestOutboxPayloadKeepsTokenAndAttachments(t *testing.T) {
+	n := OrganizationInvite(Recipient{Email: "a@example.com", Locale: "en"}, "Acme", "secret-token")
+	n.Attachments = []Attachment{{Name: "invite.ics", ContentType: "text/calendar", Data: []byte("BEGIN:VCALENDAR")}}
+
+	payload, err := encodePayload(n)
+	require.NoError(t, err)
+	decoded, err := decodePayload(payload)
+	require.NoError(t, err)
+
+	require.Equal(t, n, decoded)
+}
+
+func TestBackoffGrowsExponentiallyUpToLimit(t *testing.T) {
+	require.Equal(t, 30*time.Second, Backoff(1))
+	require.Equal(t, time.Minute, Backoff(2))
+	require.Equal(t, 4*time.Minute, Backoff(4))
+	require.Equal(t, retryMaxDelay, Backoff(20))
+}
+
+func expectClaim(mockDB sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`SELECT \* FROM "outbox_messages" WHERE \(status = \$1 AND next_attempt_at <= \$2\) .* FOR UPDATE SKIP LOCKED`).
+		WillReturnRows(rows)
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET "next_attempt_at"=.* WHERE id IN \(\$\d+,\$\d+\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDB.ExpectCommit()
+}
+
+func TestOutboxWorkerDeliversAndRetries(t *testing.T) {
+	repo, mockDB := setupOutbox(t)
+	payload, err := encodePayload(PasswordReset(Recipient{Email: "a@example.com"}, "http://localhost/reset"))
+	require.NoError(t, err)
+
+	smtp := &stubChannel{name: "smtp"}
+	webhook := &stubChannel{name: "webhook", err: errors.New("503 Service Unavailable")}
+	worker := NewOutboxWorker(repo, NewDispatcher(testLogger(), smtp, webhook), testLogger())
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	expectClaim(mockDB, sqlmock.NewRows([]string{"id", "channel", "kind", "payload", "status", "attempts"}).
+		AddRow(1, "smtp", "password_reset", payload, "pending", 0).
+		AddRow(2, "webhook", "password_reset", payload, "pending", 2))
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET "attempts"=\$1,"last_error"=\$2,"sent_at"=\$3,"status"=\$4`).
+		WithArgs(1, "", sqlmock.AnyArg(), models.OutboxSent, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET "attempts"=\$1,"last_error"=\$2,"next_attempt_at"=\$3,"status"=\$4`).
+		WithArgs(3, "503 Service Unavailable", now.Add(2*time.Minute), models.OutboxPending, sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	sent, err := worker.RunOnce(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	require.Len(t, smtp.sent, 1)
+	require.Equal(t, "Сброс пароля", smtp.sent[0].Subject)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestOutboxWorkerMovesExhaustedMessageToDeadLetter(t *testing.T) {
+	repo, mockDB := setupOutbox(t)
+	payload, err := encodePayload(Notification{Kind: KindReminder, Event: &EventDetails{Title: "Sync"}})
+	require.NoError(t, err)
+	worker := NewOutboxWorker(repo, NewDispatcher(testLogger()), testLogger())
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// канал отключили после записи сообщения: попытка считается неудачной
+	expectClaim(mockDB, sqlmock.NewRows([]string{"id", "channel", "payload", "status", "attempts"}).
+		AddRow(4, "webhook", payload, "pending", DefaultMaxAttempts-1).
+		AddRow(5, "webhook", "not json", "pending", 0))
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET .*"status"=\$4`).
+		WithArgs(DefaultMaxAttempts, sqlmock.AnyArg(), sqlmock.AnyArg(), models.OutboxDead, sqlmock.AnyArg(), 4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET .*"status"=\$4`).
+		WithArgs(1, sqlmock.AnyArg(), now.Add(retryBaseDelay), models.OutboxPending, sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+
+	sent, err := worker.RunOnce(context.Background(), now)
+	require.NoError(t, err)
+	require.Zero(t, sent)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestRequeueOnlyDeadMessagesOfOrganization(t *testing.T) {
+	repo, mockDB := setupOutbox(t)
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "outbox_messages" SET .* WHERE \(id = \$\d+ AND organization_id = \$\d+ AND status = \$\d+\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectCommit()
+
+	err := repo.Requeue(9, 5, time.Now())
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+)
+
+const (
+	// DefaultOutboxInterval период проверки outbox
+	DefaultOutboxInterval = 5 * time.Second
+	// DefaultMaxAttempts после стольких неудач сообщение уходит в dead-letter
+	DefaultMaxAttempts = 8
+	// retryBaseDelay задержка после первой неудачи, дальше удваивается
+	retryBaseDelay = 30 * time.Second
+	// retryMaxDelay предел задержки между попытками
+	retryMaxDelay = 6 * time.Hour
+	// deliveryLease время на отправку забранных сообщений, после него их заберет другой обработчик
+	deliveryLease   = 5 * time.Minute
+	outboxBatchSize = 50
+)
+
+// OutboxWorker в фоне доставляет сообщения из outbox с повторами и экспоненциальной задержкой
+type OutboxWorker struct {
+	Repository  *OutboxRepository
+	Dispatcher  *Dispatcher
+	Logger      logger.LoggerInterface
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+// NewOutboxWorker создает обработчик outbox
+func NewOutboxWorker(repo *OutboxRepository, dispatcher *Dispatcher, log logger.LoggerInterface) *OutboxWorker {
+	return &OutboxWorker{
+		Repository:  repo,
+		Dispatcher:  dispatcher,
+		Logger:      log,
+		Interval:    DefaultOutboxInterval,
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+// Backoff задержка перед следующей попыткой после attempts неудач
+func Backoff(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// Run доставляет сообщения до отмены контекста
+func (w *OutboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.RunOnce(ctx, time.Now().UTC()); err != nil {
+			w.Logger.Error("Outbox delivery failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce отправляет сообщения, которым пора уходить к моменту now, и возвращает число доставленных
+func (w *OutboxWorker) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	messages, err := w.Repository.Claim(now, deliveryLease, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, message := range messages {
+		attempts := message.Attempts + 1
+		n, err := decodePayload(message.Payload)
+		if err == nil {
+			err = w.Dispatcher.Deliver(ctx, message.Channel, n)
+		}
+		if err == nil {
+			if err := w.Repository.MarkSent(message.ID, attempts, time.Now().UTC()); err != nil {
+				return sent, err
+			}
+			sent++
+			continue
+		}
+		dead := attempts >= w.MaxAttempts
+		w.Logger.Error("Outbox message delivery failed", "id", message.ID, "channel", message.Channel, "attempts", attempts, "dead", dead, "error", err)
+		if err := w.Repository.MarkFailed(message.ID, attempts, now.Add(Backoff(attempts)), err.Error(), dead); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
@@ -0,0 +1,16 @@
+package notifier
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// NotificationPage страница ленты уведомлений
+type NotificationPage struct {
+	Items       []models.Notification `json:"items"`
+	UnreadCount int64                 `json:"unread_count"`
+	// NextCursor передается в ?cursor за следующей страницей, пустой - страниц больше нет
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// MarkAllReadResponse число уведомлений, отмеченных прочитанными
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
@@ -0,0 +1,186 @@
+package notifier
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+	// база часовых поясов встроена, чтобы время в письмах не зависело от tzdata в образе
+	_ "time/tzdata"
+)
+
+// DefaultLocale язык писем, если язык получателя не задан или не поддерживается
+const DefaultLocale = "ru"
+
+// Locales языки, для которых есть шаблоны
+var Locales = []string{"ru", "en"}
+
+// layoutTemplate общая HTML-обертка писем, шаблоны типов определяют в ней блок "content"
+const layoutTemplate = "layout.html"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// messageTemplates шаблоны одного типа уведомления на одном языке.
+// Текстовый шаблон определяет блок "subject", остальное - текст письма.
+type messageTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer заполняет тему, текст и HTML уведомлений из шаблонов
+type Renderer struct {
+	templates map[string]messageTemplates
+}
+
+// templateData данные, доступные в шаблонах: поля уведомления, язык и часовой пояс получателя.
+// Время события уже переведено в часовой пояс получателя.
+type templateData struct {
+	Notification
+	Locale   string
+	Timezone string
+}
+
+// NewRenderer загружает встроенные шаблоны. HTML-шаблон типа определяет блок "content" общей обертки.
+// Файлы из dir с тем же путем
+// (например, en/invited.html или layout.html) заменяют встроенные.
+func NewRenderer(dir string) (*Renderer, error) {
+	renderer := &Renderer{templates: map[string]messageTemplates{}}
+	layout, err := readTemplate(dir, layoutTemplate)
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range Locales {
+		for _, kind := range Kinds {
+			name := locale + "/" + string(kind)
+			textSource, err := readTemplate(dir, name+".txt")
+			if err != nil {
+				return nil, err
+			}
+			htmlSource, err := readTemplate(dir, name+".html")
+			if err != nil {
+				return nil, err
+			}
+			text, err := texttemplate.New(name + ".txt").Option("missingkey=zero").Parse(textSource)
+			if err != nil {
+				return nil, err
+			}
+			if text.Lookup("subject") == nil {
+				return nil, fmt.Errorf("template %s.txt does not define \"subject\"", name)
+			}
+			html, err := htmltemplate.New(layoutTemplate).Option("missingkey=zero").Parse(layout)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := html.New(name + ".html").Parse(htmlSource); err != nil {
+				return nil, err
+			}
+			renderer.templates[name] = messageTemplates{text: text, html: html}
+		}
+	}
+	return renderer, nil
+}
+
+var (
+	defaultRenderer     *Renderer
+	defaultRendererOnce sync.Once
+)
+
+// DefaultRenderer рендерер только со встроенными шаблонами
+func DefaultRenderer() *Renderer {
+	defaultRendererOnce.Do(func() {
+		renderer, err := NewRenderer("")
+		if err != nil {
+			// встроенные шаблоны проверяются тестами, ошибка здесь - ошибка сборки
+			panic(err)
+		}
+		defaultRenderer = renderer
+	})
+	return defaultRenderer
+}
+
+// readTemplate читает шаблон из каталога переопределений, а если его там нет - из встроенных
+func readTemplate(dir, name string) (string, error) {
+	if dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	data, err := embeddedTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Render заполняет Subject, Text и HTML уведомления на языке получателя
+func (r *Renderer) Render(n *Notification) error {
+	locale := ResolveLocale(n.Recipient.Locale)
+	tmpl, ok := r.templates[locale+"/"+string(n.Kind)]
+	if !ok {
+		return fmt.Errorf("no template for notification %q", n.Kind)
+	}
+	location := resolveLocation(n.Recipient.Timezone)
+	data := templateData{Notification: *n, Locale: locale, Timezone: location.String()}
+	if n.Event != nil {
+		ev := *n.Event
+		ev.Start = ev.Start.In(location)
+		ev.End = ev.End.In(location)
+		data.Event = &ev
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return err
+	}
+	// тема нужна и в заголовке HTML-письма
+	data.Subject = strings.TrimSpace(subject.String())
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, layoutTemplate, data); err != nil {
+		return err
+	}
+	n.Subject = data.Subject
+	n.Text = strings.TrimSpace(text.String())
+	n.HTML = html.String()
+	return nil
+}
+
+// ResolveLocale сводит язык получателя к поддерживаемому, по умолчанию DefaultLocale
+func ResolveLocale(locale string) string {
+	locale = strings.ToLower(locale)
+	// ru-RU, en_US и т.п. сводятся к языку
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	for _, supported := range Locales {
+		if locale == supported {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+func resolveLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
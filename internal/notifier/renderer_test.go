@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() EventDetails {
+	ev := &models.Event{
+		Title:          "Planning <Q3>",
+		Description:    "Roadmap",
+		StartDate:      time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		Duration:       30,
+		ConferenceLink: "https://meet.example.com/planning",
+	}
+	ev.ID = 7
+	return NewEventDetails(ev, &models.User{Username: "Anna", Email: "anna@example.com"})
+}
+
+func TestRendererRendersEveryKindInEveryLocale(t *testing.T) {
+	renderer, err := NewRenderer("")
+	require.NoError(t, err)
+	ev := testEvent()
+	notifications := []Notification{
+		Invited(Recipient{}, ev, "http://localhost/rsvp/a", "http://localhost/rsvp/d"),
+		Updated(Recipient{}, ev),
+		Cancelled(Recipient{}, ev),
+		Reminder(Recipient{}, ev),
+		PasswordReset(Recipient{}, "http://localhost/reset-password?token=t"),
+		OrganizationInvite(Recipient{}, "Acme", "token"),
+		FeedbackSurvey(Recipient{}, ev, "http://localhost/event/7/feedback"),
+		Mentioned(Recipient{}, ev),
+	}
+	require.Len(t, notifications, len(Kinds))
+	for _, locale := range Locales {
+		for _, n := range notifications {
+			n.Recipient = Recipient{Email: "bob@example.com", Locale: locale}
+			require.NoError(t, renderer.Render(&n), "%s/%s", locale, n.Kind)
+			require.NotEmpty(t, n.Subject, "%s/%s", locale, n.Kind)
+			require.NotEmpty(t, n.Text, "%s/%s", locale, n.Kind)
+			require.Contains(t, n.HTML, `<html lang="`+locale+`">`)
+			require.NotContains(t, n.Text, "<no value>", "%s/%s", locale, n.Kind)
+		}
+	}
+}
+
+func TestRendererUsesRecipientLocaleAndTimezone(t *testing.T) {
+	to := Recipient{UserID: 2, Email: "bob@example.com", Name: "Bob", Locale: "en-US", Timezone: "Europe/Moscow"}
+	n := Invited(to, testEvent(), "http://localhost/rsvp/a", "http://localhost/rsvp/d")
+	require.NoError(t, DefaultRenderer().Render(&n))
+
+	require.Equal(t, "Invitation: Planning <Q3>", n.Subject)
+	require.Contains(t, n.Text, "Anna invites you")
+	require.Contains(t, n.Text, "Mon, 02 Mar 2026 12:00 – 12:30 (Europe/Moscow)")
+	require.NotContains(t, n.Text, "No account is needed")
+	// в HTML данные экранируются, ссылки ответа оформлены кнопками
+	require.Contains(t, n.HTML, "Planning &lt;Q3&gt;")
+	require.Contains(t, n.HTML, `href="http://localhost/rsvp/a"`)
+	require.Contains(t, n.HTML, "<title>Invitation: Planning &lt;Q3&gt;</title>")
+}
+
+func TestRendererFallsBackToDefaults(t *testing.T) {
+	n := Invited(Recipient{Email: "guest@example.com", Locale: "de", Timezone: "Mars/Olympus"}, testEvent(), "a", "d")
+	require.NoError(t, DefaultRenderer().Render(&n))
+
+	require.Equal(t, "Приглашение: Planning <Q3>", n.Subject)
+	require.Contains(t, n.Text, "02.03.2026 09:00 – 09:30 (UTC)")
+	require.Contains(t, n.Text, "Учетная запись для ответа не нужна")
+}
+
+func TestRendererOverridesFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "en"), 0o755))
+	override := `{{define "subject"}}Custom: {{.Event.Title}}{{end}}Custom body`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en", "cancelled.txt"), []byte(override), 0o644))
+
+	renderer, err := NewRenderer(dir)
+	require.NoError(t, err)
+
+	n := Cancelled(Recipient{Locale: "en"}, testEvent())
+	require.NoError(t, renderer.Render(&n))
+	require.Equal(t, "Custom: Planning <Q3>", n.Subject)
+	require.Equal(t, "Custom body", n.Text)
+
+	// остальные шаблоны остаются встроенными
+	n = Cancelled(Recipient{Locale: "ru"}, testEvent())
+	require.NoError(t, renderer.Render(&n))
+	require.Equal(t, "Отменено: Planning <Q3>", n.Subject)
+}
+
+func TestRendererRejectsTemplateWithoutSubject(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ru"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ru", "reminder.txt"), []byte("no subject"), 0o644))
+
+	_, err := NewRenderer(dir)
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	DataBase *db.Db
+}
+
+// NewNotificationRepository создает новый репозиторий уведомлений в личном кабинете
+func NewNotificationRepository(dataBase *db.Db) *NotificationRepository {
+	return &NotificationRepository{DataBase: dataBase}
+}
+
+// Create сохраняет уведомление
+func (repo *NotificationRepository) Create(notification *models.Notification) error {
+	return repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(notification).Error
+}
+
+// FindByUser возвращает уведомления пользователя от новых к старым, начиная с уведомлений старше before
+// (0 - с самого нового). При unreadOnly только непрочитанные.
+func (repo *NotificationRepository) FindByUser(userID uint, unreadOnly bool, before uint, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if before != 0 {
+		query = query.Where("id < ?", before)
+	}
+	result := query.Order("id DESC").Limit(limit).Find(&notifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return notifications, nil
+}
+
+// CountUnread возвращает число непрочитанных уведомлений пользователя
+func (repo *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead отмечает уведомление пользователя прочитанным
+func (repo *NotificationRepository) MarkRead(id, userID uint, at time.Time) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllRead отмечает прочитанными все уведомления пользователя и возвращает их число
+func (repo *NotificationRepository) MarkAllRead(userID uint, at time.Time) (int64, error) {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
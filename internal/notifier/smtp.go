@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/sendmail"
+)
+
+// SMTPChannel отправляет уведомления письмом
+type SMTPChannel struct {
+	Config *configs.Config
+}
+
+// NewSMTPChannel создает почтовый канал
+func NewSMTPChannel(cfg *configs.Config) *SMTPChannel {
+	return &SMTPChannel{Config: cfg}
+}
+
+func (c *SMTPChannel) Name() string { return "smtp" }
+
+// Send отправляет письмо, если у получателя есть адрес
+func (c *SMTPChannel) Send(_ context.Context, n Notification) error {
+	if n.Recipient.Email == "" {
+		return nil
+	}
+	msg := sendmail.Message{
+		To:      n.Recipient.Email,
+		Subject: n.Subject,
+		Text:    n.Text,
+		HTML:    n.HTML,
+	}
+	for _, a := range n.Attachments {
+		msg.Attachments = append(msg.Attachments, sendmail.Attachment{Name: a.Name, ContentType: a.ContentType, Data: a.Data})
+	}
+	return sendmail.Send(c.Config, msg)
+}
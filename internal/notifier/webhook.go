@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout сколько ждем ответа внешнего сервиса
+const webhookTimeout = 5 * time.Second
+
+// WebhookChannel отправляет уведомления POST-запросом с JSON.
+// Если задан секрет, тело подписывается HMAC-SHA256 в заголовке X-Signature.
+type WebhookChannel struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// NewWebhookChannel создает канал вебхука
+func NewWebhookChannel(url, secret string) *WebhookChannel {
+	return &WebhookChannel{URL: url, Secret: secret, Client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (c *WebhookChannel) Name() string { return "webhook" }
+
+// Send отправляет уведомление без секретов (см. Notification.Redacted),
+// любой ответ кроме 2xx считается ошибкой
+func (c *WebhookChannel) Send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n.Redacted())
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(c.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
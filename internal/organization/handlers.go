@@ -0,0 +1,202 @@
+package organization
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+// inviteTTL срок действия приглашения в организацию
+const inviteTTL = 7 * 24 * time.Hour
+
+type OrganizationHandler struct {
+	OrganizationRepository *OrganizationRepository
+	UserRepository         *user.UserRepository
+	JWTService             *jwt.JWT
+	Config                 *configs.Config
+	Notifier               notifier.Notifier
+}
+
+type OrganizationHandlerDeps struct {
+	OrganizationRepository *OrganizationRepository
+	UserRepository         *user.UserRepository
+	JWTService             *jwt.JWT
+	Config                 *configs.Config
+	Notifier               notifier.Notifier
+}
+
+// NewOrganizationHandler регистрирует обработчики организаций
+func NewOrganizationHandler(mux *chi.Mux, deps OrganizationHandlerDeps) {
+	handler := &OrganizationHandler{
+		OrganizationRepository: deps.OrganizationRepository,
+		UserRepository:         deps.UserRepository,
+		JWTService:             deps.JWTService,
+		Config:                 deps.Config,
+		Notifier:               deps.Notifier,
+	}
+	mux.Handle("POST /organizations", middleware.IsAuthed(handler.CreateOrganization(), handler.JWTService))
+	mux.Handle("GET /organizations/current", middleware.IsAuthed(handler.GetCurrent(), handler.JWTService))
+	mux.Handle("POST /organizations/invites", middleware.IsAuthed(handler.Invite(), handler.JWTService))
+	mux.Handle("POST /organizations/invites/{token}/accept", middleware.IsAuthed(handler.AcceptInvite(), handler.JWTService))
+}
+
+// CreateOrganization создает организацию, текущий пользователь становится ее администратором
+func (h *OrganizationHandler) CreateOrganization() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		foundUser, ok := h.currentUser(w, r)
+		if !ok {
+			return
+		}
+		body, err := request.HandelBody[OrganizationRequest](w, r)
+		if err != nil {
+			return
+		}
+		if foundUser.OrganizationID != models.DefaultOrganizationID {
+			http.Error(w, "User already belongs to an organization", http.StatusConflict)
+			return
+		}
+		organization, err := h.OrganizationRepository.Create(&models.Organization{Name: body.Name}, foundUser.ID)
+		if err != nil {
+			http.Error(w, "Not possible to create organization", http.StatusInternalServerError)
+			return
+		}
+		h.membershipResponse(w, foundUser, organization, http.StatusCreated)
+	}
+}
+
+// GetCurrent возвращает организацию текущего пользователя
+func (h *OrganizationHandler) GetCurrent() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := middleware.OrganizationID(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if orgID == models.DefaultOrganizationID {
+			http.Error(w, "User does not belong to an organization", http.StatusNotFound)
+			return
+		}
+		organization, err := h.OrganizationRepository.FindById(orgID)
+		if err != nil {
+			http.Error(w, "Organization not found", http.StatusNotFound)
+			return
+		}
+		res.JsonResponse(w, organization, http.StatusOK)
+	}
+}
+
+// Invite приглашает пользователя в организацию по email, доступно администраторам
+func (h *OrganizationHandler) Invite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		foundUser, ok := h.currentUser(w, r)
+		if !ok {
+			return
+		}
+		body, err := request.HandelBody[InviteRequest](w, r)
+		if err != nil {
+			return
+		}
+		if foundUser.OrganizationID == models.DefaultOrganizationID || foundUser.OrgRole != models.OrgRoleAdmin {
+			http.Error(w, "Only organization admins can invite users", http.StatusForbidden)
+			return
+		}
+		organization, err := h.OrganizationRepository.FindById(foundUser.OrganizationID)
+		if err != nil {
+			http.Error(w, "Organization not found", http.StatusNotFound)
+			return
+		}
+		bytes := make([]byte, 32)
+		if _, err := rand.Read(bytes); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		invite, err := h.OrganizationRepository.CreateInvite(&models.OrganizationInvite{
+			OrganizationID: organization.ID,
+			Email:          strings.ToLower(body.Email),
+			Token:          hex.EncodeToString(bytes),
+			InvitedBy:      foundUser.ID,
+			ExpiresAt:      time.Now().Add(inviteTTL),
+		})
+		if err != nil {
+			http.Error(w, "Not possible to create invite", http.StatusInternalServerError)
+			return
+		}
+		//адресат может еще не быть зарегистрирован: письмо приходит на языке и в часовом поясе пригласившего
+		to := notifier.Recipient{Email: invite.Email, Locale: foundUser.Locale, Timezone: foundUser.Timezone}
+		h.Notifier.Notify(r.Context(), notifier.OrganizationInvite(to, organization.Name, invite.Token))
+		res.JsonResponse(w, invite, http.StatusCreated)
+	}
+}
+
+// AcceptInvite принимает приглашение: пользователь с email из приглашения вступает в организацию
+func (h *OrganizationHandler) AcceptInvite() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		foundUser, ok := h.currentUser(w, r)
+		if !ok {
+			return
+		}
+		invite, err := h.OrganizationRepository.FindInviteByToken(chi.URLParam(r, "token"))
+		if err != nil {
+			http.Error(w, "Invite not found", http.StatusNotFound)
+			return
+		}
+		if !invite.IsActive(time.Now()) || !strings.EqualFold(invite.Email, foundUser.Email) {
+			http.Error(w, "Invite is not valid", http.StatusForbidden)
+			return
+		}
+		if foundUser.OrganizationID != models.DefaultOrganizationID {
+			http.Error(w, "User already belongs to an organization", http.StatusConflict)
+			return
+		}
+		if err := h.OrganizationRepository.AcceptInvite(invite, foundUser.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.membershipResponse(w, foundUser, invite.Organization, http.StatusOK)
+	}
+}
+
+// currentUser загружает авторизованного пользователя
+func (h *OrganizationHandler) currentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	foundUser, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return nil, false
+	}
+	return foundUser, true
+}
+
+// membershipResponse выдает новые токены с организацией пользователя
+func (h *OrganizationHandler) membershipResponse(w http.ResponseWriter, u *models.User, organization *models.Organization, status int) {
+	tokenPair, err := h.JWTService.GenerateTokenPair(jwt.JWTData{
+		Email:          u.Email,
+		UserID:         u.ID,
+		OrganizationID: organization.ID,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res.JsonResponse(w, &MembershipResponse{
+		Organization: organization,
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+	}, status)
+}
@@ -0,0 +1,54 @@
+package organization
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteIsActive(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	invite := &models.OrganizationInvite{ExpiresAt: now.Add(time.Hour)}
+	require.True(t, invite.IsActive(now))
+	require.False(t, invite.IsActive(now.Add(2*time.Hour)))
+
+	invite.AcceptedAt = &now
+	require.False(t, invite.IsActive(now))
+}
+
+func TestAcceptInviteMovesUser(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "organization_invites" SET "accepted_at"=$1,"updated_at"=$2 WHERE "organization_invites"."deleted_at" IS NULL AND "id" = $3`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "org_role"=$1,"organization_id"=$2,"updated_at"=$3 WHERE id = $4 AND "users"."deleted_at" IS NULL`)).
+		WithArgs(models.OrgRoleMember, uint(2), sqlmock.AnyArg(), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "delegations" SET "deleted_at"=$1 WHERE (owner_id = $2 OR delegate_id = $3) AND "delegations"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), uint(9), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "calendar_shares" SET "deleted_at"=$1 WHERE (owner_id = $2 OR grantee_user_id = $3) AND "calendar_shares"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), uint(9), uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "group_members" WHERE user_id = $1`)).
+		WithArgs(uint(9)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	repo := NewOrganizationRepository(&db.Db{DB: gormDB})
+	invite := &models.OrganizationInvite{OrganizationID: 2, Email: "new@example.com"}
+	invite.ID = 5
+	err := repo.AcceptInvite(invite, 9)
+	require.NoError(t, err)
+	require.NotNil(t, invite.AcceptedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,20 @@
+package organization
+
+import "github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+
+// OrganizationRequest данные для создания организации
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// InviteRequest данные для приглашения пользователя в организацию
+type InviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// MembershipResponse организация пользователя и новые токены: в них уже записан тенант
+type MembershipResponse struct {
+	Organization *models.Organization `json:"organization"`
+	AccessToken  string               `json:"access_token"`
+	RefreshToken string               `json:"refresh_token"`
+}
@@ -0,0 +1,99 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	DataBase *db.Db
+}
+
+// NewOrganizationRepository создает новый репозиторий организаций
+func NewOrganizationRepository(dataBase *db.Db) *OrganizationRepository {
+	return &OrganizationRepository{DataBase: dataBase}
+}
+
+// Create создает организацию, ее создатель переходит в нее администратором
+func (repo *OrganizationRepository) Create(organization *models.Organization, ownerID uint) (*models.Organization, error) {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return moveUser(tx, ownerID, organization.ID, models.OrgRoleAdmin)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// FindById находит организацию по ID
+func (repo *OrganizationRepository) FindById(id uint) (*models.Organization, error) {
+	var organization models.Organization
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).First(&organization, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &organization, nil
+}
+
+// CreateInvite сохраняет приглашение в организацию
+func (repo *OrganizationRepository) CreateInvite(invite *models.OrganizationInvite) (*models.OrganizationInvite, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(invite)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return invite, nil
+}
+
+// FindInviteByToken находит приглашение по токену
+func (repo *OrganizationRepository) FindInviteByToken(token string) (*models.OrganizationInvite, error) {
+	var invite models.OrganizationInvite
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Preload("Organization").
+		Where("token = ?", token).
+		First(&invite)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invite, nil
+}
+
+// AcceptInvite переводит пользователя в организацию из приглашения и отмечает приглашение принятым
+func (repo *OrganizationRepository) AcceptInvite(invite *models.OrganizationInvite, userID uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(invite).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+		invite.AcceptedAt = &now
+		return moveUser(tx, userID, invite.OrganizationID, models.OrgRoleMember)
+	})
+}
+
+// moveUser переводит пользователя в организацию. Доступы, выданные в прежней организации,
+// отзываются, чтобы через них нельзя было увидеть данные другого тенанта.
+// События, созданные до перехода, остаются в прежней организации.
+func moveUser(tx *gorm.DB, userID, organizationID uint, role models.OrgRole) error {
+	err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"organization_id": organizationID,
+		"org_role":        role,
+	}).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("owner_id = ? OR delegate_id = ?", userID, userID).Delete(&models.Delegation{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("owner_id = ? OR grantee_user_id = ?", userID, userID).Delete(&models.CalendarShare{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.GroupMember{}).Error
+}
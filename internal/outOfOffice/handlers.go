@@ -0,0 +1,112 @@
+package outOfOffice
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+type OutOfOfficeHandler struct {
+	OutOfOfficeRepository *OutOfOfficeRepository
+	JWTService            *jwt.JWT
+	Delegations           *delegation.DelegationRepository
+}
+
+type OutOfOfficeHandlerDeps struct {
+	OutOfOfficeRepository *OutOfOfficeRepository
+	JWTService            *jwt.JWT
+	Delegations           *delegation.DelegationRepository
+}
+
+// NewOutOfOfficeHandler регистрирует обработчики периодов отсутствия
+func NewOutOfOfficeHandler(mux *chi.Mux, deps OutOfOfficeHandlerDeps) {
+	handler := &OutOfOfficeHandler{
+		OutOfOfficeRepository: deps.OutOfOfficeRepository,
+		JWTService:            deps.JWTService,
+		Delegations:           deps.Delegations,
+	}
+	mux.Handle("GET /out-of-office", middleware.IsAuthedAs(handler.List(), handler.JWTService, handler.Delegations))
+	mux.Handle("POST /out-of-office", middleware.IsAuthedAs(handler.Create(), handler.JWTService, handler.Delegations))
+	mux.Handle("DELETE /out-of-office/{id}", middleware.IsAuthedAs(handler.Delete(), handler.JWTService, handler.Delegations))
+}
+
+// List возвращает текущие и будущие периоды отсутствия пользователя
+func (h *OutOfOfficeHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		periods, err := h.OutOfOfficeRepository.FindByUser(userID, time.Now())
+		if err != nil {
+			http.Error(w, "Failed to fetch out-of-office periods", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, periods, http.StatusOK)
+	}
+}
+
+// Create объявляет период отсутствия пользователя
+func (h *OutOfOfficeHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[OutOfOfficeRequest](w, r)
+		if err != nil {
+			return
+		}
+		start, err := request.ValidateTime(body.StartDate)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		end, err := request.ValidateTime(body.EndDate)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !end.After(start) {
+			http.Error(w, "end_date must be after start_date", http.StatusBadRequest)
+			return
+		}
+		period, err := h.OutOfOfficeRepository.Create(models.NewOutOfOffice(userID, start, end, body.Message))
+		if err != nil {
+			http.Error(w, "Not possible to create out-of-office period", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, period, http.StatusCreated)
+	}
+}
+
+// Delete удаляет период отсутствия пользователя
+func (h *OutOfOfficeHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.OutOfOfficeRepository.Delete(id, userID); err != nil {
+			http.Error(w, "Out-of-office period not found", http.StatusNotFound)
+			return
+		}
+		res.JsonResponse(w, "Out-of-office period deleted", http.StatusOK)
+	}
+}
@@ -0,0 +1,50 @@
+package outOfOffice
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const overlappingQuery = `SELECT * FROM "out_of_offices" WHERE (user_id = $1 AND start_date < $2 AND end_date > $3) AND "out_of_offices"."deleted_at" IS NULL ORDER BY start_date,"out_of_offices"."id" LIMIT $4`
+
+func TestFindOverlapping(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(overlappingQuery)).
+		WithArgs(uint(2), end, start, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_date", "end_date", "message"}).
+			AddRow(1, 2, start.AddDate(0, 0, -3), start.AddDate(0, 0, 7), "On vacation until July 8"))
+
+	repo := NewOutOfOfficeRepository(&db.Db{DB: gormDB})
+	period, err := repo.FindOverlapping(2, start, end)
+	require.NoError(t, err)
+	require.NotNil(t, period)
+	require.Equal(t, "On vacation until July 8", period.Message)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFindOverlappingNone(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+	start := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta(overlappingQuery)).
+		WithArgs(uint(2), end, start, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOutOfOfficeRepository(&db.Db{DB: gormDB})
+	period, err := repo.FindOverlapping(2, start, end)
+	require.NoError(t, err)
+	require.Nil(t, period)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
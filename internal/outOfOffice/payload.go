@@ -0,0 +1,8 @@
+package outOfOffice
+
+// OutOfOfficeRequest период отсутствия, время в формате 2006-01-02 15:04
+type OutOfOfficeRequest struct {
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date" validate:"required"`
+	Message   string `json:"message" validate:"max=500"`
+}
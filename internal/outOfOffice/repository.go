@@ -0,0 +1,75 @@
+package outOfOffice
+
+import (
+	"errors"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type OutOfOfficeRepository struct {
+	DataBase *db.Db
+}
+
+// NewOutOfOfficeRepository создает новый репозиторий периодов отсутствия
+func NewOutOfOfficeRepository(dataBase *db.Db) *OutOfOfficeRepository {
+	return &OutOfOfficeRepository{DataBase: dataBase}
+}
+
+// Create сохраняет период отсутствия
+func (repo *OutOfOfficeRepository) Create(period *models.OutOfOffice) (*models.OutOfOffice, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(period)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return period, nil
+}
+
+// FindByUser возвращает периоды отсутствия пользователя, которые еще не закончились
+func (repo *OutOfOfficeRepository) FindByUser(userID uint, now time.Time) ([]models.OutOfOffice, error) {
+	var periods []models.OutOfOffice
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("user_id = ? AND end_date > ?", userID, now).
+		Order("start_date").
+		Find(&periods)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return periods, nil
+}
+
+// FindOverlapping возвращает период отсутствия, пересекающийся с интервалом [start, end).
+// Если пользователь в это время на месте, возвращается nil.
+func (repo *OutOfOfficeRepository) FindOverlapping(userID uint, start, end time.Time) (*models.OutOfOffice, error) {
+	var period models.OutOfOffice
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("user_id = ? AND start_date < ? AND end_date > ?", userID, end, start).
+		Order("start_date").
+		First(&period)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &period, nil
+}
+
+// Delete удаляет период отсутствия пользователя
+func (repo *OutOfOfficeRepository) Delete(id, userID uint) error {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.OutOfOffice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
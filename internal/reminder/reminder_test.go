@@ -0,0 +1,51 @@
+package reminder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnceRemindsOnceInSameTransaction(t *testing.T) {
+	gormDB, mockDB, cleanup := mock.SetupMockDB(t)
+	defer cleanup()
+	database := &db.Db{DB: gormDB}
+	outbox := notifier.NewOutbox(notifier.NewOutboxRepository(database), []string{"inapp"})
+	worker := NewWorker(NewReminderRepository(database), outbox, logger.NewLogger(&configs.Config{}))
+
+	now := time.Date(2026, 3, 2, 8, 50, 0, 0, time.UTC)
+	start := now.Add(10 * time.Minute)
+	mockDB.ExpectQuery(`SELECT ep.id AS participant_id, .* FROM event_participants ep .*e\.start_date > \$\d+ AND e.start_date <= \$\d+`).
+		WithArgs("Принято", now, now.Add(DefaultLead), 100).
+		WillReturnRows(sqlmock.NewRows([]string{"participant_id", "user_id", "email", "locale", "event_id", "organization_id", "title", "start_date", "duration"}).
+			AddRow(11, 3, "a@example.com", "en", 7, 2, "Sync", start, 30).
+			AddRow(12, 4, "b@example.com", "ru", 7, 2, "Sync", start, 30))
+
+	//первому участнику напоминание записывается в outbox той же транзакцией
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "event_participants" SET "reminded_for"=\$1,"updated_at"=\$2 WHERE \(id = \$3 AND \(reminded_for IS NULL OR reminded_for <> \$4\)\)`).
+		WithArgs(start, sqlmock.AnyArg(), uint(11), start).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectQuery(`INSERT INTO "outbox_messages"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, uint(2), "inapp", "reminder", "a@example.com", sqlmock.AnyArg(), "pending", 0, sqlmock.AnyArg(), "", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mockDB.ExpectCommit()
+	//второму уже напомнил другой экземпляр обработчика
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "event_participants" SET "reminded_for"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mockDB.ExpectCommit()
+
+	sent, err := worker.RunOnce(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+	require.NoError(t, mockDB.ExpectationsWereMet())
+}
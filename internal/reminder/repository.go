@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+// Due участник встречи, которому пора напомнить о ее начале
+type Due struct {
+	ParticipantID  uint
+	UserID         uint
+	Email          string
+	Username       string
+	Locale         string
+	Timezone       string
+	EventID        uint
+	OrganizationID uint
+	Title          string
+	Description    string
+	StartDate      time.Time
+	Duration       int
+	ConferenceLink string
+	Organizer      string
+}
+
+type ReminderRepository struct {
+	DataBase *db.Db
+}
+
+// NewReminderRepository создает новый репозиторий напоминаний
+func NewReminderRepository(dataBase *db.Db) *ReminderRepository {
+	return &ReminderRepository{DataBase: dataBase}
+}
+
+// FindDue возвращает принявших приглашение участников встреч, начинающихся в (from, until],
+// которым еще не напомнили об этом времени начала. Блоки фокус-времени пропускаются.
+func (repo *ReminderRepository) FindDue(from, until time.Time, limit int) ([]Due, error) {
+	var due []Due
+	err := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Table("event_participants ep").
+		Select("ep.id AS participant_id, ep.user_id, u.email, u.username, u.locale, u.timezone, "+
+			"e.id AS event_id, e.organization_id, e.title, e.description, e.start_date, e.duration, e.conference_link, "+
+			"o.username AS organizer").
+		Joins("JOIN events e ON e.id = ep.event_id AND e.deleted_at IS NULL").
+		Joins("JOIN users u ON u.id = ep.user_id AND u.deleted_at IS NULL").
+		Joins("LEFT JOIN users o ON o.id = e.creator_id").
+		Where("ep.deleted_at IS NULL AND ep.status = ? AND e.focus = false", models.StatusAccepted).
+		Where("e.start_date > ? AND e.start_date <= ?", from, until).
+		Where("ep.reminded_for IS NULL OR ep.reminded_for <> e.start_date").
+		Order("e.start_date, ep.id").
+		Limit(limit).
+		Scan(&due).Error
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// MarkReminded фиксирует напоминание о начале start. Возвращает false, если напоминание
+// уже отправил другой экземпляр обработчика.
+func (repo *ReminderRepository) MarkReminded(participantID uint, start time.Time) (bool, error) {
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&models.EventParticipant{}).
+		Where("id = ? AND (reminded_for IS NULL OR reminded_for <> ?)", participantID, start).
+		Update("reminded_for", start)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
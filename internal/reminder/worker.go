@@ -0,0 +1,118 @@
+package reminder
+
+import (
+	"context"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultInterval период проверки ближайших встреч
+	DefaultInterval = time.Minute
+	// DefaultLead за сколько до начала встречи приходит напоминание
+	DefaultLead = 15 * time.Minute
+	batchSize   = 100
+)
+
+// Worker в фоне напоминает участникам о скором начале встреч
+type Worker struct {
+	Repository *ReminderRepository
+	Notifier   notifier.Notifier
+	Logger     logger.LoggerInterface
+	Interval   time.Duration
+	Lead       time.Duration
+}
+
+// NewWorker создает обработчик напоминаний
+func NewWorker(repo *ReminderRepository, notify notifier.Notifier, log logger.LoggerInterface) *Worker {
+	return &Worker{
+		Repository: repo,
+		Notifier:   notify,
+		Logger:     log,
+		Interval:   DefaultInterval,
+		Lead:       DefaultLead,
+	}
+}
+
+// Run проверяет ближайшие встречи до отмены контекста
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.RunOnce(ctx, time.Now().UTC()); err != nil {
+			w.Logger.Error("Meeting reminders failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce напоминает о встречах, начинающихся в ближайшие Lead после now, и возвращает число напоминаний
+func (w *Worker) RunOnce(ctx context.Context, now time.Time) (int, error) {
+	due, err := w.Repository.FindDue(now, now.Add(w.Lead), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, d := range due {
+		//отметка и уведомление сохраняются одной транзакцией: напоминание не потеряется и не уйдет дважды
+		var reminded bool
+		err := w.Repository.DataBase.DB.Transaction(func(tx *gorm.DB) error {
+			txDB := &db.Db{DB: tx}
+			claimed, err := NewReminderRepository(txDB).MarkReminded(d.ParticipantID, d.StartDate)
+			if err != nil || !claimed {
+				return err
+			}
+			notify := w.Notifier
+			if transactional, ok := notify.(notifier.Transactional); ok {
+				notify = transactional.WithTx(txDB)
+			}
+			//напоминание относится к организации встречи
+			orgCtx := context.WithValue(ctx, middleware.ContextOrgIDKey, d.OrganizationID)
+			if err := notify.Notify(orgCtx, notifier.Reminder(d.recipient(), d.details())); err != nil {
+				return err
+			}
+			reminded = true
+			return nil
+		})
+		if err != nil {
+			w.Logger.Error("Failed to send meeting reminder", "event_id", d.EventID, "user_id", d.UserID, "error", err)
+			continue
+		}
+		if reminded {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (d Due) recipient() notifier.Recipient {
+	return notifier.UserRecipient(&models.User{
+		Model:    gorm.Model{ID: d.UserID},
+		Email:    d.Email,
+		Username: d.Username,
+		Locale:   d.Locale,
+		Timezone: d.Timezone,
+	})
+}
+
+func (d Due) details() notifier.EventDetails {
+	ev := &models.Event{
+		Model:          gorm.Model{ID: d.EventID},
+		Title:          d.Title,
+		Description:    d.Description,
+		StartDate:      d.StartDate,
+		Duration:       d.Duration,
+		ConferenceLink: d.ConferenceLink,
+	}
+	return notifier.NewEventDetails(ev, &models.User{Username: d.Organizer})
+}
@@ -0,0 +1,134 @@
+package tag
+
+import (
+	"net/http"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/convert"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/res"
+	"github.com/go-chi/chi/v5"
+)
+
+type TagHandler struct {
+	TagRepository  *TagRepository
+	UserRepository *user.UserRepository
+	JWTService     *jwt.JWT
+}
+
+type TagHandlerDeps struct {
+	TagRepository  *TagRepository
+	UserRepository *user.UserRepository
+	JWTService     *jwt.JWT
+}
+
+// NewTagHandler регистрирует обработчики меток
+func NewTagHandler(mux *chi.Mux, deps TagHandlerDeps) {
+	handler := &TagHandler{
+		TagRepository:  deps.TagRepository,
+		UserRepository: deps.UserRepository,
+		JWTService:     deps.JWTService,
+	}
+	mux.Handle("GET /tags", middleware.IsAuthed(handler.List(), handler.JWTService))
+	mux.Handle("POST /tags", middleware.IsAuthed(handler.Create(), handler.JWTService))
+	mux.Handle("DELETE /tags/{id}", middleware.IsAuthed(handler.Delete(), handler.JWTService))
+}
+
+// List возвращает метки организации и личные метки пользователя
+func (h *TagHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		tags, err := h.TagRepository.FindAvailable(userID, orgID)
+		if err != nil {
+			http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, tags, http.StatusOK)
+	}
+}
+
+// Create создает личную метку или метку организации, общие метки создают администраторы
+func (h *TagHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, err := request.HandelBody[TagRequest](w, r)
+		if err != nil {
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		ownerID := &userID
+		if body.Organization {
+			if !h.isAdmin(w, userID) {
+				return
+			}
+			ownerID = nil
+		}
+		created, err := h.TagRepository.Create(models.NewTag(orgID, ownerID, body.Name, body.Color))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, created, http.StatusCreated)
+	}
+}
+
+// Delete удаляет метку: личную - владелец, общую - администратор организации
+func (h *TagHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		id, err := convert.ParseId(r, "id")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		orgID, _ := middleware.OrganizationID(r.Context())
+		foundTag, err := h.TagRepository.FindInOrganization(id, orgID)
+		if err != nil {
+			http.Error(w, "Tag not found", http.StatusNotFound)
+			return
+		}
+		if foundTag.OwnerID == nil {
+			if !h.isAdmin(w, userID) {
+				return
+			}
+		} else if *foundTag.OwnerID != userID {
+			http.Error(w, "Only the owner can delete the tag", http.StatusForbidden)
+			return
+		}
+		if err := h.TagRepository.DeleteById(id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		res.JsonResponse(w, "Tag deleted", http.StatusOK)
+	}
+}
+
+// isAdmin проверяет, что пользователь администратор организации
+func (h *TagHandler) isAdmin(w http.ResponseWriter, userID uint) bool {
+	foundUser, err := h.UserRepository.FindByid(userID)
+	if err != nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return false
+	}
+	if foundUser.OrganizationID == models.DefaultOrganizationID || foundUser.OrgRole != models.OrgRoleAdmin {
+		http.Error(w, "Only organization admins can manage organization tags", http.StatusForbidden)
+		return false
+	}
+	return true
+}
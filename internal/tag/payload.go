@@ -0,0 +1,8 @@
+package tag
+
+// TagRequest новая метка, Organization делает метку общей для организации
+type TagRequest struct {
+	Name         string `json:"name" validate:"required,max=64"`
+	Color        string `json:"color" validate:"required,hexcolor,len=7"`
+	Organization bool   `json:"organization"`
+}
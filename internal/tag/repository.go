@@ -0,0 +1,113 @@
+package tag
+
+import (
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"gorm.io/gorm"
+)
+
+type TagRepository struct {
+	DataBase *db.Db
+}
+
+// NewTagRepository создает новый репозиторий меток
+func NewTagRepository(dataBase *db.Db) *TagRepository {
+	return &TagRepository{DataBase: dataBase}
+}
+
+// Create сохраняет новую метку
+func (repo *TagRepository) Create(tag *models.Tag) (*models.Tag, error) {
+	result := repo.DataBase.DB.Session(&gorm.Session{NewDB: true}).Create(tag)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tag, nil
+}
+
+// FindAvailable возвращает метки организации и личные метки пользователя
+func (repo *TagRepository) FindAvailable(userID, organizationID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("organization_id = ? AND (owner_id IS NULL OR owner_id = ?)", organizationID, userID).
+		Order("name").
+		Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindInOrganization находит метку по ID только внутри организации
+func (repo *TagRepository) FindInOrganization(id, organizationID uint) (*models.Tag, error) {
+	var tag models.Tag
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("organization_id = ?", organizationID).
+		First(&tag, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// FindByIds возвращает метки с указанными ID
+func (repo *TagRepository) FindByIds(ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id IN ?", ids).
+		Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindEventTags возвращает связи меток с указанными событиями
+func (repo *TagRepository) FindEventTags(eventIDs []uint) ([]models.EventTag, error) {
+	var links []models.EventTag
+	if len(eventIDs) == 0 {
+		return links, nil
+	}
+	result := repo.DataBase.DB.
+		Session(&gorm.Session{NewDB: true}).
+		Where("event_id IN ?", eventIDs).
+		Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+// SetEventTags заменяет метки события на переданный набор
+func (repo *TagRepository) SetEventTags(eventID uint, tagIDs []uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&models.EventTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		links := make([]models.EventTag, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			links = append(links, models.EventTag{EventID: eventID, TagID: tagID})
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+// DeleteById удаляет метку и снимает ее со всех событий
+func (repo *TagRepository) DeleteById(id uint) error {
+	db := repo.DataBase.DB.Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&models.EventTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
@@ -0,0 +1,60 @@
+package tag
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/models"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAvailable(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tags" WHERE (organization_id = $1 AND (owner_id IS NULL OR owner_id = $2)) AND "tags"."deleted_at" IS NULL ORDER BY name`)).
+		WithArgs(uint(3), uint(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "owner_id", "name", "color"}).
+			AddRow(1, 3, nil, "клиент", "#ff0000").
+			AddRow(2, 3, 7, "личное", "#00ff00"))
+
+	repo := NewTagRepository(&db.Db{DB: gormDB})
+	tags, err := repo.FindAvailable(7, 3)
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	require.Nil(t, tags[0].OwnerID)
+	require.Equal(t, "#00ff00", tags[1].Color)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetEventTags(t *testing.T) {
+	gormDB, mock, cleanup := mock.SetupMockDB(t)
+	t.Cleanup(cleanup)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "event_tags" WHERE event_id = $1`)).
+		WithArgs(uint(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "event_tags" ("event_id","tag_id") VALUES ($1,$2),($3,$4)`)).
+		WithArgs(uint(5), uint(1), uint(5), uint(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	repo := NewTagRepository(&db.Db{DB: gormDB})
+	require.NoError(t, repo.SetEventTags(5, []uint{1, 2}))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTagUsableBy(t *testing.T) {
+	owner := uint(7)
+	orgTag := models.NewTag(3, nil, "найм", "#0000ff")
+	personal := models.NewTag(3, &owner, "личное", "#00ff00")
+
+	require.True(t, orgTag.UsableBy(9, 3))
+	require.False(t, orgTag.UsableBy(9, 4))
+	require.True(t, personal.UsableBy(7, 3))
+	require.False(t, personal.UsableBy(9, 3))
+}
@@ -29,15 +29,20 @@ func NewUserHandler(mux *chi.Mux, deps UserHandlerDeps) {
 		UserRepository: deps.UserRepository,
 		JWTService:     deps.JWTService,
 	}
-	mux.Handle("GET /users", handler.GetAllUsers())
+	mux.Handle("GET /users", middleware.IsAuthed(handler.GetAllUsers(), handler.JWTService))
 	mux.Handle("GET /user/{id}", middleware.IsAuthed(handler.GetUserByID(), handler.JWTService))
 	mux.Handle("PUT /user/{id}", middleware.IsAuthed(handler.UpdateDataUser(), handler.JWTService))
 	mux.Handle("DELETE /user/{id}", middleware.IsAuthed(handler.DeleteUser(), handler.JWTService))
 }
 
-// получение всех пользователей
+// получение всех пользователей своей организации
 func (handler *UserHandler) GetAllUsers() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		orgID, ok := middleware.OrganizationID(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
 
 		limit := 20
 		offset := 0
@@ -79,7 +84,7 @@ func (handler *UserHandler) GetAllUsers() http.HandlerFunc {
 			return
 		}
 
-		allUsers, total, err := handler.UserRepository.FindAllUsers(limit, offset, search)
+		allUsers, total, err := handler.UserRepository.FindAllUsers(orgID, limit, offset, search)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -108,7 +113,12 @@ func (handler *UserHandler) GetUserByID() http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		user, err := handler.UserRepository.FindByid(id)
+		orgID, ok := middleware.OrganizationID(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		user, err := handler.UserRepository.FindInOrganization(id, orgID)
 		if err != nil {
 			http.Error(w, "Failed search user by id", http.StatusBadRequest)
 			return
@@ -157,6 +167,8 @@ func (handler *UserHandler) UpdateDataUser() http.HandlerFunc {
 			Username: body.Username,
 			Password: string(hashedPassword),
 			Email:    body.Email,
+			Timezone: body.Timezone,
+			Locale:   body.Locale,
 		}
 		var updatedUser *models.User
 		//Проверяем что обновляем именно авторизованного юзера, а не кого другого
@@ -47,6 +47,7 @@ func TestGetAllUser(t *testing.T) {
 	r.Get("/users", handler.GetAllUsers())
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextOrgIDKey, uint(0)))
 	r.ServeHTTP(w, req)
 	require.Equal(t, w.Code, 200)
 	require.NoError(t, mockDB.ExpectationsWereMet())
@@ -64,6 +65,7 @@ func TestGetUserByID(t *testing.T) {
 	r.Get("/user/{id}", handler.GetUserByID())
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/user/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextOrgIDKey, uint(0)))
 
 	r.ServeHTTP(w, req)
 	t.Logf("Response code: %d, body: %s", w.Code, w.Body.String())
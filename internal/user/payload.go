@@ -6,6 +6,9 @@ type UserUpdateRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	Email    string `gorm:"unique index" json:"email" validate:"required,email"`
+	// Timezone и Locale необязательны: пустые значения не меняют настройки
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	Locale   string `json:"locale" validate:"omitempty,oneof=ru en"`
 }
 
 type UserPaginatedResponse struct {
@@ -45,9 +45,9 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	return &u, nil
 }
 
-// FindAllUsers находит всех пользователей в базе данных.
-func (repo *UserRepository) FindAllUsers(limit, offset int, search string) ([]models.UserResponse, int64, error) {
-	result := repo.DataBase.DB.Model(&models.User{}).Where("deleted_at is null")
+// FindAllUsers находит всех пользователей организации в базе данных.
+func (repo *UserRepository) FindAllUsers(organizationID uint, limit, offset int, search string) ([]models.UserResponse, int64, error) {
+	result := repo.DataBase.DB.Model(&models.User{}).Where("deleted_at is null AND organization_id = ?", organizationID)
 
 	if search != "" {
 		search = strings.TrimSpace(search)
@@ -71,11 +71,18 @@ func (repo *UserRepository) FindAllUsers(limit, offset int, search string) ([]mo
 
 // Update обновляет информацию о пользователе в базе данных.
 func (repo *UserRepository) Update(user *models.User) (*models.User, error) {
-	result := repo.DataBase.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+	fields := map[string]interface{}{
 		"username": user.Username,
 		"password": user.Password,
 		"email":    user.Email,
-	})
+	}
+	if user.Timezone != "" {
+		fields["timezone"] = user.Timezone
+	}
+	if user.Locale != "" {
+		fields["locale"] = user.Locale
+	}
+	result := repo.DataBase.DB.Model(&models.User{}).Where("id = ?", user.ID).Updates(fields)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -106,3 +113,16 @@ func (r *UserRepository) FindByid(id uint) (*models.User, error) {
 	}
 	return &u, nil
 }
+
+// FindInOrganization находит пользователя по id только внутри указанной организации.
+func (r *UserRepository) FindInOrganization(id, organizationID uint) (*models.User, error) {
+	var u models.User
+	err := r.DataBase.
+		Session(&gorm.Session{NewDB: true}).
+		Where("id = ? AND organization_id = ?", id, organizationID).
+		First(&u).Error
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -20,7 +20,7 @@ func TestCreateUser(t *testing.T) {
 	defer t.Cleanup(cleanup)
 	mock.ExpectBegin()
 	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "testuser", "password", "email@example.com").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "testuser", "password", "email@example.com", uint(0), models.OrgRoleMember, "UTC", "ru").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
 	mock.ExpectCommit()
@@ -69,21 +69,21 @@ func TestFindAllUsers(t *testing.T) {
 	// Ожидаем COUNT(*)
 	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT count(*) FROM "users" WHERE deleted_at is null`,
-	)).WillReturnRows(countRows)
+		`SELECT count(*) FROM "users" WHERE (deleted_at is null AND organization_id = $1) AND "users"."deleted_at" IS NULL`,
+	)).WithArgs(uint(3)).WillReturnRows(countRows)
 
 	// Ожидаем SELECT-запрос для получения всех пользователей.
 	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "username", "email"}).
 		AddRow(1, fixedTime, fixedTime, "testuser", "email@example.com")
 
-	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "users"."id","users"."created_at","users"."updated_at","users"."username","users"."email" 
-	FROM "users" WHERE deleted_at is null AND "users"."deleted_at" IS NULL LIMIT $1`)).
-	WithArgs(20).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "users"."id","users"."created_at","users"."updated_at","users"."username","users"."email","users"."organization_id","users"."org_role","users"."timezone","users"."locale" 
+	FROM "users" WHERE (deleted_at is null AND organization_id = $1) AND "users"."deleted_at" IS NULL LIMIT $2`)).
+	WithArgs(uint(3), 20).WillReturnRows(rows)
 
 	dbWrapper := &db.Db{DB: gormDB}
 	repo := user.NewUserRepository(dbWrapper)
 
-	users, total, err := repo.FindAllUsers(20, 0, "")
+	users, total, err := repo.FindAllUsers(3, 20, 0, "")
 
 	require.NoError(t, err, "Error fetching users")
 	require.Equal(t, int64(1), total)
@@ -9,16 +9,32 @@ import (
 
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/cmd"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/analytics"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/app"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/auth"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendar"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/calendarShare"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/delegation"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/event"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventGuest"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventHistory"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/eventParticipant"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/feedback"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/focusTime"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/group"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/holiday"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/logger"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/notifier"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/organization"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/outOfOffice"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/passwordReset"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/reminder"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/secret"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/server"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/tag"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/internal/user"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/migrations"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/conferencing"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/db"
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
 	"github.com/go-chi/chi/v5"
@@ -30,6 +46,14 @@ type AppComponents struct {
 	App    *app.App
 	Router *chi.Mux
 	Server *server.Server
+	// FeedbackWorker рассылает опросы после встреч
+	FeedbackWorker *feedback.Worker
+	// FocusTimeWorker ставит и переносит блоки фокус-времени
+	FocusTimeWorker *focusTime.Worker
+	// OutboxWorker доставляет уведомления из outbox
+	OutboxWorker *notifier.OutboxWorker
+	// ReminderWorker напоминает участникам о скором начале встреч
+	ReminderWorker *reminder.Worker
 }
 
 func setupApplication() *AppComponents {
@@ -48,6 +72,9 @@ func setupApplication() *AppComponents {
 	userRepo := user.NewUserRepository(database)
 	secretRepo := secret.NewSecretRepository(database, log)
 	passwordReset := passwordReset.NewPasswordResetRepository(database, log)
+	delegationRepo := delegation.NewDelegationRepository(database)
+	calendarShareRepo := calendarShare.NewCalendarShareRepository(database)
+	calendarRepo := calendar.NewCalendarRepository(database)
 
 	// Создаем JWT сервис с настройками
 	jwtService := jwt.NewJWT(cfg.Auth.Secret)
@@ -55,8 +82,20 @@ func setupApplication() *AppComponents {
 	jwtService.AccessTokenTTL = time.Minute * 4
 	jwtService.RefreshTokenTTL = time.Minute * 5
 
+	// Уведомления: каналы доставки задаются в NOTIFY_CHANNELS.
+	// Обработчики пишут уведомления в outbox, доставляет их OutboxWorker.
+	notificationRepo := notifier.NewNotificationRepository(database)
+	dispatcher, err := notifier.NewFromConfig(cfg, notificationRepo, log)
+	if err != nil {
+		log.Error("Notifier configuration failed", "error", err)
+		os.Exit(1)
+	}
+	outboxRepo := notifier.NewOutboxRepository(database)
+	notify := notifier.NewOutbox(outboxRepo, dispatcher.ChannelNames())
+
 	// Инициализация сервисов
-	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService)
+	guestRepo := eventGuest.NewEventGuestRepository(database)
+	authService := auth.NewAuthService(userRepo, secretRepo, passwordReset, jwtService, notify)
 
 	//Инициализация миграций
 	migrations.InitModelMigration()
@@ -71,33 +110,158 @@ func setupApplication() *AppComponents {
 		JWTService:     jwtService,
 	})
 
+	organization.NewOrganizationHandler(router, organization.OrganizationHandlerDeps{
+		OrganizationRepository: organization.NewOrganizationRepository(database),
+		UserRepository:         userRepo,
+		JWTService:             jwtService,
+		Config:                 cfg,
+		Notifier:               notify,
+	})
+
+	notifier.NewNotificationHandler(router, notifier.NotificationHandlerDeps{
+		NotificationRepository: notificationRepo,
+		JWTService:             jwtService,
+	})
+	notifier.NewOutboxHandler(router, notifier.OutboxHandlerDeps{
+		OutboxRepository: outboxRepo,
+		UserRepository:   userRepo,
+		JWTService:       jwtService,
+	})
+
+	delegation.NewDelegationHandler(router, delegation.DelegationHandlerDeps{
+		DelegationRepository: delegationRepo,
+		UserRepository:       userRepo,
+		JWTService:           jwtService,
+	})
+
+	// Репозиторий групп
+	groupRepo := group.NewGroupRepository(database)
+
+	calendarShare.NewCalendarShareHandler(router, calendarShare.CalendarShareHandlerDeps{
+		CalendarShareRepository: calendarShareRepo,
+		UserRepository:          userRepo,
+		GroupRepository:         groupRepo,
+		JWTService:              jwtService,
+	})
+
+	calendar.NewCalendarHandler(router, calendar.CalendarHandlerDeps{
+		CalendarRepository: calendarRepo,
+		JWTService:         jwtService,
+		Delegations:        delegationRepo,
+	})
+
 	// Инициализация репозитория событий
 	eventRepo := event.NewEventRepository(database)
 
 	// Инициализация репозитория участников событий
 	eventParticipantRepo := eventParticipant.NewEventParticipantRepository(database)
 
+	// История изменений событий
+	historyRepo := eventHistory.NewEventHistoryRepository(database)
+
+	// Периоды отсутствия пользователей
+	outOfOfficeRepo := outOfOffice.NewOutOfOfficeRepository(database)
+	outOfOffice.NewOutOfOfficeHandler(router, outOfOffice.OutOfOfficeHandlerDeps{
+		OutOfOfficeRepository: outOfOfficeRepo,
+		JWTService:            jwtService,
+		Delegations:           delegationRepo,
+	})
+
+	// Праздничные календари организаций
+	holidayRepo := holiday.NewHolidayRepository(database)
+
+	// Фокус-время пользователей
+	focusTimeRepo := focusTime.NewFocusTimeRepository(database)
+	focusTime.NewFocusTimeHandler(router, focusTime.FocusTimeHandlerDeps{
+		FocusTimeRepository: focusTimeRepo,
+		HolidayRepository:   holidayRepo,
+		UserRepository:      userRepo,
+		JWTService:          jwtService,
+		Delegations:         delegationRepo,
+	})
+
+	// Метки событий
+	tagRepo := tag.NewTagRepository(database)
+	tag.NewTagHandler(router, tag.TagHandlerDeps{
+		TagRepository:  tagRepo,
+		UserRepository: userRepo,
+		JWTService:     jwtService,
+	})
+
+	// Опросы после встреч
+	feedbackRepo := feedback.NewFeedbackRepository(database)
+
+	// Провайдер видеовстреч
+	conferencingProvider := conferencing.NewJitsiProvider(cfg.Conferencing.BaseURL)
+
 	// Регистрация обработчиков событий
-	event.NewEventHandler(router, event.EventHandlerDeps{
+	eventDeps := event.EventHandlerDeps{
 		EventRepository:  eventRepo,
 		UserRepository:   userRepo,
 		EventParticipant: eventParticipantRepo,
 		JWTService:       jwtService,
 		Config:           cfg,
+		Conferencing:     conferencingProvider,
+		Delegations:      delegationRepo,
+		CalendarShares:   calendarShareRepo,
+		Calendars:        calendarRepo,
+		Groups:           groupRepo,
+		OutOfOffice:      outOfOfficeRepo,
+		Holidays:         holidayRepo,
+		History:          historyRepo,
+		Tags:             tagRepo,
+		Feedback:         feedbackRepo,
+		Guests:           guestRepo,
+		Notifier:         notify,
+	}
+	event.NewEventHandler(router, eventDeps)
+
+	// Регистрация обработчиков групп
+	group.NewGroupHandler(router, group.GroupHandlerDeps{
+		GroupRepository: groupRepo,
+		UserRepository:  userRepo,
+		Invites:         event.NewGroupInviter(eventDeps),
+		JWTService:      jwtService,
+		Delegations:     delegationRepo,
+	})
+
+	// Аналитика нагрузки встречами
+	analytics.NewAnalyticsHandler(router, analytics.AnalyticsHandlerDeps{
+		AnalyticsRepository: analytics.NewAnalyticsRepository(database),
+		UserRepository:      userRepo,
+		GroupRepository:     groupRepo,
+		Tags:                tagRepo,
+		JWTService:          jwtService,
+	})
+
+	// Регистрация обработчиков праздничных календарей
+	holiday.NewHolidayHandler(router, holiday.HolidayHandlerDeps{
+		HolidayRepository: holidayRepo,
+		UserRepository:    userRepo,
+		GroupRepository:   groupRepo,
+		JWTService:        jwtService,
 	})
 
 	// Регистрация обработчиков участников событий
 	eventParticipant.NewEventParticipantHandler(router, eventParticipant.EventParticipantDepsHandler{
 		EventParticipantRepository: eventParticipantRepo,
 		JWTService:                 jwtService,
+		Delegations:                delegationRepo,
+		History:                    historyRepo,
+		CalendarShares:             calendarShareRepo,
+		Invites:                    event.NewParticipantInviter(eventDeps),
 	})
 
 	return &AppComponents{
-		Config: cfg,
-		Logger: log,
-		App:    application,
-		Router: router,
-		Server: srv,
+		Config:          cfg,
+		Logger:          log,
+		App:             application,
+		Router:          router,
+		Server:          srv,
+		FeedbackWorker:  feedback.NewWorker(feedbackRepo, cfg, notify, log),
+		FocusTimeWorker: focusTime.NewWorker(focusTimeRepo, holidayRepo, log),
+		OutboxWorker:    notifier.NewOutboxWorker(outboxRepo, dispatcher, log),
+		ReminderWorker:  reminder.NewWorker(reminder.NewReminderRepository(database), notify, log),
 	}
 
 }
@@ -111,6 +275,11 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	go components.FeedbackWorker.Run(ctx)
+	go components.FocusTimeWorker.Run(ctx)
+	go components.OutboxWorker.Run(ctx)
+	go components.ReminderWorker.Run(ctx)
+
 	if err := components.Server.Start(ctx); err != nil {
 		components.Logger.Info(err.Error())
 	}
@@ -151,11 +151,13 @@ func EventParticipantModelInit(db *gorm.DB, logger logger.LoggerInterface, event
 				EventID: events[i].ID,
 				UserID:  events[i].CreatorID,
 				Status:  models.StatusAccepted,
+				Role:    models.RoleOrganizer,
 			},
 			{
 				EventID: events[i+1].ID,
 				UserID:  events[i+1].CreatorID,
 				Status:  models.StatusAccepted,
+				Role:    models.RoleOrganizer,
 			},
 		}
 		break
@@ -223,3 +225,76 @@ func PreviousPasswordModelInit(db *gorm.DB, log logger.LoggerInterface) error {
 	}
 	return nil
 }
+
+// SchemaUpgrade добавляет в существующие таблицы колонки, появившиеся в моделях после их создания
+func SchemaUpgrade(db *gorm.DB, logger logger.LoggerInterface) error {
+	//метки событий хранятся в таблице связи с собственной моделью
+	if err := db.SetupJoinTable(&models.Event{}, "Tags", &models.EventTag{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Organization{},
+		&models.OrganizationInvite{},
+		&models.Calendar{},
+		&models.Event{},
+		&models.EventParticipant{},
+		&models.Delegation{},
+		&models.DelegationAudit{},
+		&models.CalendarShare{},
+		&models.Group{},
+		&models.GroupMember{},
+		&models.EventGroup{},
+		&models.OutOfOffice{},
+		&models.HolidayCalendar{},
+		&models.Holiday{},
+		&models.HolidayAssignment{},
+		&models.EventRevision{},
+		&models.Tag{},
+		&models.EventTag{},
+		&models.FeedbackSurvey{},
+		&models.FocusTimeGoal{},
+		&models.EventGuest{},
+		&models.Notification{},
+		&models.OutboxMessage{},
+	); err != nil {
+		return err
+	}
+	if err := eventSearchUpgrade(db); err != nil {
+		return err
+	}
+	logger.Info("Schema upgraded")
+	return nil
+}
+
+// eventSearchUpgrade добавляет в events поисковый вектор по названию, описанию, повестке и протоколу
+// на русском и английском и GIN-индекс для полнотекстового поиска.
+// Вектор, созданный до появления повестки и протокола, пересоздается вместе с индексом
+func eventSearchUpgrade(db *gorm.DB) error {
+	var upToDate bool
+	if err := db.Raw(`SELECT NOT EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'events' AND column_name = 'search_vector'
+			AND generation_expression NOT LIKE '%minutes%'
+	)`).Scan(&upToDate).Error; err != nil {
+		return err
+	}
+	if !upToDate {
+		if err := db.Exec(`ALTER TABLE events DROP COLUMN search_vector`).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.Exec(`ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(agenda, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(agenda, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(minutes, '')), 'C') ||
+		setweight(to_tsvector('english', coalesce(minutes, '')), 'C')
+	) STORED`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector)`).Error
+}
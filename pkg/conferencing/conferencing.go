@@ -0,0 +1,68 @@
+package conferencing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+const defaultJitsiURL = "https://meet.jit.si"
+
+// Provider определяет интерфейс провайдера видеоконференций
+type Provider interface {
+	// CreateMeeting создает комнату для встречи и возвращает ссылку для подключения
+	CreateMeeting(title string) (string, error)
+	// ReleaseMeeting освобождает комнату, если встреча отменена или перенесена
+	ReleaseMeeting(link string) error
+}
+
+// JitsiProvider генерирует ссылки на комнаты в стиле Jitsi без обращения к внешним сервисам
+type JitsiProvider struct {
+	BaseURL string
+}
+
+// Убедимся, что JitsiProvider реализует интерфейс Provider
+var _ Provider = (*JitsiProvider)(nil)
+
+// NewJitsiProvider создает провайдера Jitsi, по умолчанию используется meet.jit.si
+func NewJitsiProvider(baseURL string) *JitsiProvider {
+	if baseURL == "" {
+		baseURL = defaultJitsiURL
+	}
+	return &JitsiProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CreateMeeting генерирует случайное имя комнаты на основе названия события
+func (p *JitsiProvider) CreateMeeting(title string) (string, error) {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	room := "MeetingPro"
+	if prefix := roomPrefix(title); prefix != "" {
+		room += "-" + prefix
+	}
+	return p.BaseURL + "/" + room + "-" + hex.EncodeToString(bytes), nil
+}
+
+// ReleaseMeeting для Jitsi ничего не делает: комнаты не регистрируются заранее
+func (p *JitsiProvider) ReleaseMeeting(link string) error {
+	return nil
+}
+
+// roomPrefix оставляет в названии только латинские буквы и цифры
+func roomPrefix(title string) string {
+	var b strings.Builder
+	for _, r := range title {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+		if b.Len() >= 20 {
+			break
+		}
+	}
+	return b.String()
+}
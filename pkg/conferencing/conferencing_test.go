@@ -0,0 +1,30 @@
+package conferencing_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/conferencing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJitsiCreateMeeting(t *testing.T) {
+	provider := conferencing.NewJitsiProvider("https://meet.example.com/")
+
+	first, err := provider.CreateMeeting("Daily sync")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(first, "https://meet.example.com/MeetingPro-Dailysync-"), first)
+
+	second, err := provider.CreateMeeting("Daily sync")
+	require.NoError(t, err)
+	require.NotEqual(t, first, second, "ссылки на комнаты должны быть уникальными")
+}
+
+func TestJitsiDefaultURL(t *testing.T) {
+	provider := conferencing.NewJitsiProvider("")
+
+	link, err := provider.CreateMeeting("Планерка")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(link, "https://meet.jit.si/MeetingPro-"), link)
+	require.NoError(t, provider.ReleaseMeeting(link))
+}
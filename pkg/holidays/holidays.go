@@ -0,0 +1,147 @@
+package holidays
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout формат даты праздника в JSON
+const DateLayout = "2006-01-02"
+
+// Day праздничный или нерабочий день
+type Day struct {
+	Date time.Time `json:"date"`
+	Name string    `json:"name"`
+}
+
+// ErrUnknownFormat файл не является ни ICS, ни JSON
+var ErrUnknownFormat = errors.New("holiday calendar must be an .ics or .json file")
+
+// Parse читает календарь праздников, формат определяется по расширению файла
+func Parse(filename string, r io.Reader) ([]Day, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics":
+		return ParseICS(r)
+	case ".json":
+		return ParseJSON(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ParseJSON читает список праздников вида [{"date": "2025-01-01", "name": "Новый год"}]
+func ParseJSON(r io.Reader) ([]Day, error) {
+	var items []struct {
+		Date string `json:"date"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+	days := make([]Day, 0, len(items))
+	for _, item := range items {
+		date, err := time.Parse(DateLayout, item.Date)
+		if err != nil {
+			return nil, fmt.Errorf("wrong holiday date %q, format should be %s", item.Date, DateLayout)
+		}
+		days = append(days, Day{Date: date, Name: item.Name})
+	}
+	return normalize(days), nil
+}
+
+// ParseICS читает события VEVENT из календаря iCalendar. Каждое событие дает праздничные дни
+// с DTSTART по DTEND не включительно, как принято для событий на целый день.
+func ParseICS(r io.Reader) ([]Day, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var days []Day
+	var inEvent bool
+	var start, end time.Time
+	var name string
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// параметры свойства (например ;VALUE=DATE) для разбора не нужны
+		key, _, _ = strings.Cut(strings.ToUpper(key), ";")
+		switch {
+		case key == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			start, end, name = time.Time{}, time.Time{}, ""
+		case key == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+			if start.IsZero() {
+				return nil, errors.New("holiday event without DTSTART")
+			}
+			if !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+				days = append(days, Day{Date: date, Name: name})
+			}
+		case !inEvent:
+			continue
+		case key == "DTSTART":
+			if start, err = parseICSDate(value); err != nil {
+				return nil, err
+			}
+		case key == "DTEND":
+			if end, err = parseICSDate(value); err != nil {
+				return nil, err
+			}
+		case key == "SUMMARY":
+			name = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\\`, `\`).Replace(value)
+		}
+	}
+	return normalize(days), nil
+}
+
+// unfold склеивает перенесенные строки iCalendar: продолжение начинается с пробела или табуляции
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseICSDate разбирает дату или дату со временем, время отбрасывается
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("wrong holiday date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("wrong holiday date %q", value)
+	}
+	return date, nil
+}
+
+// normalize сортирует дни и убирает повторы одной даты
+func normalize(days []Day) []Day {
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	result := days[:0]
+	for _, day := range days {
+		if len(result) > 0 && result[len(result)-1].Date.Equal(day.Date) {
+			continue
+		}
+		result = append(result, day)
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package holidays
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseICS(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20250101\r\n" +
+		"DTEND;VALUE=DATE:20250103\r\n" +
+		"SUMMARY:Новогодние\r\n" +
+		"  каникулы\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART:20250612T000000Z\r\n" +
+		"SUMMARY:День России\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	days, err := ParseICS(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), days[0].Date)
+	require.Equal(t, "Новогодние каникулы", days[0].Name)
+	require.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), days[1].Date)
+	require.Equal(t, time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC), days[2].Date)
+	require.Equal(t, "День России", days[2].Name)
+}
+
+func TestParseJSON(t *testing.T) {
+	days, err := Parse("ru.json", strings.NewReader(`[
+		{"date": "2025-05-09", "name": "День Победы"},
+		{"date": "2025-05-01", "name": "Праздник Весны и Труда"},
+		{"date": "2025-05-01", "name": "повтор"}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	require.Equal(t, "Праздник Весны и Труда", days[0].Name)
+	require.Equal(t, time.Date(2025, 5, 9, 0, 0, 0, 0, time.UTC), days[1].Date)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("holidays.csv", strings.NewReader(""))
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = ParseJSON(strings.NewReader(`[{"date": "01.01.2025"}]`))
+	require.Error(t, err)
+}
@@ -0,0 +1,103 @@
+package ics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateTimeLayout формат даты и времени в UTC по RFC 5545
+const dateTimeLayout = "20060102T150405Z"
+
+// Method тип сообщения iTIP
+type Method string
+
+const (
+	MethodRequest Method = "REQUEST"
+	MethodCancel  Method = "CANCEL"
+)
+
+// Invite приглашение на встречу для почтовых клиентов
+type Invite struct {
+	UID         string
+	Sequence    int
+	Method      Method
+	Title       string
+	Description string
+	Start       time.Time
+	Duration    time.Duration
+	URL         string
+	Organizer   string
+	Attendee    string
+}
+
+// Build формирует календарь с одним событием. Строки длиннее 75 байт переносятся.
+func Build(invite Invite, now time.Time) []byte {
+	method := invite.Method
+	if method == "" {
+		method = MethodRequest
+	}
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//metiing-pro//RU",
+		"CALSCALE:GREGORIAN",
+		"METHOD:" + string(method),
+		"BEGIN:VEVENT",
+		"UID:" + invite.UID,
+		fmt.Sprintf("SEQUENCE:%d", invite.Sequence),
+		"DTSTAMP:" + now.UTC().Format(dateTimeLayout),
+		"DTSTART:" + invite.Start.UTC().Format(dateTimeLayout),
+		"DTEND:" + invite.Start.Add(invite.Duration).UTC().Format(dateTimeLayout),
+		"SUMMARY:" + escape(invite.Title),
+	}
+	if invite.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escape(invite.Description))
+	}
+	if invite.URL != "" {
+		lines = append(lines, "URL:"+invite.URL)
+	}
+	if invite.Organizer != "" {
+		lines = append(lines, "ORGANIZER:mailto:"+invite.Organizer)
+	}
+	if invite.Attendee != "" {
+		lines = append(lines, "ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:"+invite.Attendee)
+	}
+	if method == MethodCancel {
+		lines = append(lines, "STATUS:CANCELLED")
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(fold(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// escape экранирует спецсимволы текстовых значений
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// fold переносит строку по 75 байт, не разрывая символы UTF-8
+func fold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		//продолжение строки начинается с пробела, он тоже занимает место
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package ics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/ics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	start := time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC)
+	data := string(ics.Build(ics.Invite{
+		UID:         "event-7@metiing-pro",
+		Title:       "Встреча, с клиентом",
+		Description: strings.Repeat("описание ", 20),
+		Start:       start,
+		Duration:    90 * time.Minute,
+		Attendee:    "client@example.com",
+	}, start))
+
+	require.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\n"))
+	require.Contains(t, data, "METHOD:REQUEST\r\n")
+	require.Contains(t, data, "DTSTART:20250602T100000Z\r\n")
+	require.Contains(t, data, "DTEND:20250602T113000Z\r\n")
+	require.Contains(t, data, `SUMMARY:Встреча\, с клиентом`)
+	//после склейки перенесенных строк адрес участника целый
+	require.Contains(t, strings.ReplaceAll(data, "\r\n ", ""), "RSVP=TRUE:mailto:client@example.com")
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75, line)
+	}
+}
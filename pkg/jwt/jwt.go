@@ -9,6 +9,8 @@ import (
 type JWTData struct {
 	Email  string
 	UserID uint
+	// OrganizationID организация (тенант) пользователя
+	OrganizationID uint
 }
 
 type TokenPair struct {
@@ -34,6 +36,7 @@ func (j *JWT) generateToken(data JWTData) (string, error) {
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"email":   data.Email,
 		"user_id": data.UserID,
+		"org_id":  data.OrganizationID,
 		"exp":     time.Now().Add(j.AccessTokenTTL).Unix(),
 	})
 	return t.SignedString([]byte(j.Secret))
@@ -85,10 +88,13 @@ func (j *JWT) ParseToken(token string) (bool, *JWTData) {
 	if !ok1 || !ok2 {
 		return false, nil
 	}
+	// токены без организации относятся к организации по умолчанию
+	orgIDFloat, _ := claims["org_id"].(float64)
 
 	return true, &JWTData{
-		Email:  email,
-		UserID: uint(userIDFloat),
+		Email:          email,
+		UserID:         uint(userIDFloat),
+		OrganizationID: uint(orgIDFloat),
 	}
 }
 
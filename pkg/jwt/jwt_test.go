@@ -57,6 +57,24 @@ func TestParseAccessToken(t *testing.T) {
 
 }
 
+func TestParseAccessTokenOrganization(t *testing.T) {
+	jwtService := NewJWT("test-secret")
+
+	tokenPair, err := jwtService.GenerateTokenPair(JWTData{
+		Email:          "test@example.com",
+		UserID:         7,
+		OrganizationID: 3,
+	})
+
+	require.NoError(t, err)
+
+	isValid, data := jwtService.ParseToken(tokenPair.AccessToken)
+
+	require.True(t, isValid)
+	require.Equal(t, uint(7), data.UserID)
+	require.Equal(t, uint(3), data.OrganizationID)
+}
+
 func TestParseRefreshToken(t *testing.T) {
 	const email = "test@example.com"
 	jwtService := NewJWT("test-secret")
@@ -11,8 +11,10 @@ import (
 type key string
 
 const (
-	ContextEmailKey  key = "ContextEmailKey"
-	ContextUserIDKey key = "ContextUserIDKey"
+	ContextEmailKey   key = "ContextEmailKey"
+	ContextUserIDKey  key = "ContextUserIDKey"
+	ContextActorIDKey key = "ContextActorIDKey"
+	ContextOrgIDKey   key = "ContextOrgIDKey"
 )
 
 func writeUnauthorized(w http.ResponseWriter) {
@@ -35,9 +37,16 @@ func IsAuthed(next http.Handler, jwtService *jwt.JWT) http.Handler {
 			return
 		}
 
-		// кладём в контекст и Email, и UserID
+		// кладём в контекст Email, UserID и организацию пользователя
 		ctx := context.WithValue(r.Context(), ContextEmailKey, data.Email)
 		ctx = context.WithValue(ctx, ContextUserIDKey, data.UserID)
+		ctx = context.WithValue(ctx, ContextOrgIDKey, data.OrganizationID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OrganizationID возвращает организацию авторизованного пользователя
+func OrganizationID(ctx context.Context) (uint, bool) {
+	orgID, ok := ctx.Value(ContextOrgIDKey).(uint)
+	return orgID, ok
+}
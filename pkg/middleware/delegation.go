@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const (
+	// OnBehalfOfHeader заголовок с ID пользователя, от имени которого выполняется запрос
+	OnBehalfOfHeader = "X-On-Behalf-Of"
+	// OnBehalfOfParam параметр запроса с ID пользователя, от имени которого выполняется запрос
+	OnBehalfOfParam = "on_behalf_of"
+)
+
+// DelegationChecker проверяет права помощника и ведет журнал его действий
+type DelegationChecker interface {
+	CanActFor(ownerID, delegateID uint, method string) (bool, error)
+	RecordDelegatedAction(actorID, ownerID uint, method, path string, status int) error
+}
+
+// IsAuthedAs работает как IsAuthed и дополнительно позволяет действовать от имени другого пользователя
+func IsAuthedAs(next http.Handler, jwtService *jwt.JWT, delegations DelegationChecker) http.Handler {
+	return IsAuthed(ActAs(next, delegations), jwtService)
+}
+
+// ActAs подменяет пользователя в контексте на владельца календаря, если помощник передал
+// заголовок X-On-Behalf-Of или параметр on_behalf_of и имеет нужный уровень доступа.
+// Сам помощник сохраняется в контексте под ключом ContextActorIDKey.
+func ActAs(next http.Handler, delegations DelegationChecker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		onBehalfOf := r.Header.Get(OnBehalfOfHeader)
+		if onBehalfOf == "" {
+			onBehalfOf = r.URL.Query().Get(OnBehalfOfParam)
+		}
+		actorID, ok := r.Context().Value(ContextUserIDKey).(uint)
+		if onBehalfOf == "" || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ownerID64, err := strconv.ParseUint(onBehalfOf, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid "+OnBehalfOfHeader+" value", http.StatusBadRequest)
+			return
+		}
+		ownerID := uint(ownerID64)
+		if ownerID == actorID {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if delegations == nil {
+			http.Error(w, "Delegation is not supported", http.StatusForbidden)
+			return
+		}
+		allowed, err := delegations.CanActFor(ownerID, actorID, r.Method)
+		if err != nil {
+			http.Error(w, "Error checking delegation", http.StatusInternalServerError)
+			return
+		}
+		if !allowed {
+			http.Error(w, "No delegation to act on behalf of this user", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ContextUserIDKey, ownerID)
+		ctx = context.WithValue(ctx, ContextActorIDKey, actorID)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		// журнал не должен влиять на уже отправленный ответ
+		_ = delegations.RecordDelegatedAction(actorID, ownerID, r.Method, r.URL.Path, status)
+	})
+}
+
+// ActorID возвращает пользователя, фактически выполнившего запрос: помощника при делегировании,
+// иначе самого авторизованного пользователя
+func ActorID(ctx context.Context) uint {
+	if actorID, ok := ctx.Value(ContextActorIDKey).(uint); ok {
+		return actorID
+	}
+	userID, _ := ctx.Value(ContextUserIDKey).(uint)
+	return userID
+}
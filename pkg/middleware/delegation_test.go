@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/jwt"
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/middleware"
+)
+
+type fakeDelegations struct {
+	allowed  bool
+	recorded []int
+}
+
+func (f *fakeDelegations) CanActFor(ownerID, delegateID uint, method string) (bool, error) {
+	return f.allowed && ownerID == 7 && delegateID == 42, nil
+}
+
+func (f *fakeDelegations) RecordDelegatedAction(actorID, ownerID uint, method, path string, status int) error {
+	f.recorded = append(f.recorded, status)
+	return nil
+}
+
+func TestIsAuthedAsDelegated(t *testing.T) {
+	newJWT := jwt.NewJWT("secret")
+	pair, err := newJWT.GenerateTokenPair(jwt.JWTData{UserID: 42, Email: "assistant@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegations := &fakeDelegations{allowed: true}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID := r.Context().Value(middleware.ContextUserIDKey).(uint)
+		if userID != 7 || middleware.ActorID(r.Context()) != 42 {
+			t.Errorf("ожидали владельца 7 и помощника 42, получили %d и %d", userID, middleware.ActorID(r.Context()))
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "http://example.com/event/", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	req.Header.Set(middleware.OnBehalfOfHeader, "7")
+	rr := httptest.NewRecorder()
+	middleware.IsAuthedAs(next, newJWT, delegations).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Errorf("ожидали 201, получили %d", rr.Code)
+	}
+	if len(delegations.recorded) != 1 || delegations.recorded[0] != http.StatusCreated {
+		t.Errorf("действие помощника не попало в журнал: %v", delegations.recorded)
+	}
+}
+
+func TestIsAuthedAsForbidden(t *testing.T) {
+	newJWT := jwt.NewJWT("secret")
+	pair, err := newJWT.GenerateTokenPair(jwt.JWTData{UserID: 42, Email: "assistant@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("дошли до хендлера — а не должны были!")
+	})
+
+	req := httptest.NewRequest("GET", "http://example.com/event/1?on_behalf_of=8", nil)
+	req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	rr := httptest.NewRecorder()
+	middleware.IsAuthedAs(next, newJWT, &fakeDelegations{allowed: true}).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("ожидали 403, получили %d", rr.Code)
+	}
+}
@@ -0,0 +1,29 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag формирует строгий ETag из версии ресурса
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatch проверяет заголовок If-Match против текущего ETag ресурса.
+// Без заголовка условие считается выполненным, "*" совпадает с любой версией.
+func IfMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		//слабые ETag не подходят для строгого сравнения
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package request_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/request"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatch(t *testing.T) {
+	etag := request.ETag(3)
+	require.Equal(t, `"3"`, etag)
+
+	cases := map[string]bool{
+		"":             true,
+		"*":            true,
+		`"3"`:          true,
+		`"2", "3"`:     true,
+		`"2"`:          false,
+		`W/"3"`:        false,
+		`"2", W/"3"`:   false,
+		` "1" , "3" `:  true,
+		`"33"`:         false,
+		`"3", invalid`: true,
+	}
+	for header, expected := range cases {
+		req := httptest.NewRequest("PUT", "/event/1", nil)
+		if header != "" {
+			req.Header.Set("If-Match", header)
+		}
+		require.Equal(t, expected, request.IfMatch(req, etag), header)
+	}
+}
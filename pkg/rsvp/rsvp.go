@@ -0,0 +1,120 @@
+package rsvp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid rsvp token")
+	ErrExpiredToken = errors.New("rsvp token expired")
+)
+
+// Action ответ, который выполняет ссылка
+type Action string
+
+const (
+	ActionAccept  Action = "accept"
+	ActionDecline Action = "decline"
+)
+
+// Claims содержимое токена: одна ссылка - одно событие, один получатель и одно действие.
+// Получатель - пользователь (UserID) или внешний гость (GuestID).
+type Claims struct {
+	EventID uint
+	UserID  uint
+	GuestID uint
+	Action  Action
+	// Start начало события на момент выпуска ссылки: после переноса события ссылка устаревает
+	Start   time.Time
+	Expires time.Time
+}
+
+// DeriveKey выводит из секрета сервера отдельный ключ для подписи ссылок ответа,
+// чтобы ссылки из писем не подписывались тем же ключом, что и токены авторизации
+func DeriveKey(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("rsvp-link"))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Sign выпускает подписанный токен ответа на приглашение. Токен не требует авторизации,
+// подпись HMAC-SHA256 на ключе из DeriveKey защищает его от подмены.
+func Sign(key string, claims Claims) string {
+	payload := strings.Join([]string{
+		strconv.FormatUint(uint64(claims.EventID), 10),
+		strconv.FormatUint(uint64(claims.UserID), 10),
+		strconv.FormatUint(uint64(claims.GuestID), 10),
+		string(claims.Action),
+		strconv.FormatInt(claims.Start.Unix(), 10),
+		strconv.FormatInt(claims.Expires.Unix(), 10),
+	}, ".")
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + signature(key, encoded)
+}
+
+// Verify проверяет подпись и срок действия токена и возвращает его содержимое.
+// Совпадение Start с текущим началом события проверяет вызывающий.
+func Verify(key, token string, now time.Time) (Claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(key, encoded))) {
+		return Claims{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	parts := strings.Split(string(payload), ".")
+	if len(parts) != 6 {
+		return Claims{}, ErrInvalidToken
+	}
+	ids := make([]uint, 3)
+	for i := range ids {
+		id, err := strconv.ParseUint(parts[i], 10, 64)
+		if err != nil {
+			return Claims{}, ErrInvalidToken
+		}
+		ids[i] = uint(id)
+	}
+	start, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(parts[5], 10, 64)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	claims := Claims{
+		EventID: ids[0],
+		UserID:  ids[1],
+		GuestID: ids[2],
+		Action:  Action(parts[3]),
+		Start:   time.Unix(start, 0).UTC(),
+		Expires: time.Unix(expires, 0).UTC(),
+	}
+	if claims.Action != ActionAccept && claims.Action != ActionDecline {
+		return Claims{}, ErrInvalidToken
+	}
+	//получатель должен быть ровно один
+	if (claims.UserID == 0) == (claims.GuestID == 0) {
+		return Claims{}, ErrInvalidToken
+	}
+	if now.After(claims.Expires) {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+func signature(key, encoded string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
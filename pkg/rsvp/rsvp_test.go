@@ -0,0 +1,55 @@
+package rsvp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/rsvp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	claims := rsvp.Claims{EventID: 7, UserID: 42, Action: rsvp.ActionAccept, Start: now.Add(30 * time.Minute), Expires: now.Add(time.Hour)}
+	token := rsvp.Sign("secret", claims)
+
+	verified, err := rsvp.Verify("secret", token, now)
+	require.NoError(t, err)
+	require.Equal(t, claims, verified)
+
+	_, err = rsvp.Verify("other", token, now)
+	require.ErrorIs(t, err, rsvp.ErrInvalidToken)
+
+	_, err = rsvp.Verify("secret", token, now.Add(2*time.Hour))
+	require.ErrorIs(t, err, rsvp.ErrExpiredToken)
+
+	_, err = rsvp.Verify("secret", "garbage", now)
+	require.ErrorIs(t, err, rsvp.ErrInvalidToken)
+}
+
+func TestVerifyRejectsTamperedAction(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	accept := rsvp.Sign("secret", rsvp.Claims{EventID: 7, UserID: 42, Action: rsvp.ActionAccept, Expires: now.Add(time.Hour)})
+	decline := rsvp.Sign("secret", rsvp.Claims{EventID: 7, UserID: 42, Action: rsvp.ActionDecline, Expires: now.Add(time.Hour)})
+
+	//подпись одной ссылки не подходит к содержимому другой
+	payload, _, _ := strings.Cut(decline, ".")
+	_, sig, _ := strings.Cut(accept, ".")
+	_, err := rsvp.Verify("secret", payload+"."+sig, now)
+	require.ErrorIs(t, err, rsvp.ErrInvalidToken)
+}
+
+func TestVerifyRequiresSingleRecipient(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	token := rsvp.Sign("secret", rsvp.Claims{EventID: 7, UserID: 42, GuestID: 3, Action: rsvp.ActionAccept, Expires: now.Add(time.Hour)})
+	_, err := rsvp.Verify("secret", token, now)
+	require.ErrorIs(t, err, rsvp.ErrInvalidToken)
+}
+
+func TestDeriveKey(t *testing.T) {
+	//ключ ссылок не совпадает с секретом и зависит от него
+	require.NotEqual(t, "secret", rsvp.DeriveKey("secret"))
+	require.Equal(t, rsvp.DeriveKey("secret"), rsvp.DeriveKey("secret"))
+	require.NotEqual(t, rsvp.DeriveKey("secret"), rsvp.DeriveKey("other"))
+}
@@ -1,22 +1,50 @@
 package sendmail
 
 import (
+	"bytes"
 	"net/smtp"
 
 	"github.com/PurpleSchoolPractice/metiing-pro-golang/configs"
 	"github.com/jordan-wright/email"
 )
 
-func SendMail(config *configs.Config, accept, decline string) error {
+// Attachment вложение письма
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Message письмо одному получателю. HTML необязателен.
+type Message struct {
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Send отправляет письмо через SMTP-сервер из конфигурации
+func Send(config *configs.Config, msg Message) error {
 	e := email.NewEmail()
 	e.From = config.EmailSender.Email
-	e.To = []string{"tes@test.ru"}
+	e.To = []string{msg.To}
+	e.Subject = msg.Subject
 
-	e.Subject = "Awesome Subject"
-	body := decline + " " + accept
-	e.Text = []byte(body)
+	e.Text = []byte(msg.Text)
+	if msg.HTML != "" {
+		e.HTML = []byte(msg.HTML)
+	}
+	for _, a := range msg.Attachments {
+		if _, err := e.Attach(bytes.NewReader(a.Data), a.Name, a.ContentType); err != nil {
+			return err
+		}
+	}
 
-	err := e.Send(config.EmailSender.SmtpWithPort, smtp.PlainAuth("", config.EmailSender.Email, config.EmailSender.Password, config.EmailSender.Smtp))
+	err := e.Send(
+		config.EmailSender.SmtpWithPort,
+		smtp.PlainAuth("", config.EmailSender.Email, config.EmailSender.Password, config.EmailSender.Smtp),
+	)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,55 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/PurpleSchoolPractice/metiing-pro-golang/pkg/xlsx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := xlsx.NewWriter(&buf, "Events & people")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow("title", "duration"))
+	require.NoError(t, w.WriteRow("Бюджет <Q3>", 30, time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), nil))
+	require.NoError(t, w.Close())
+	require.ErrorIs(t, w.WriteRow("late"), xlsx.ErrClosed)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files["xl/workbook.xml"], `name="Events &amp; people"`)
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	require.Contains(t, sheet, `<row r="2">`)
+	require.Contains(t, sheet, `Бюджет &lt;Q3&gt;`)
+	require.Contains(t, sheet, `<c><v>30</v></c>`)
+	require.Contains(t, sheet, `2025-06-02 10:00`)
+
+	//каждая часть книги должна быть корректным XML
+	for name, body := range files {
+		decoder := xml.NewDecoder(bytes.NewReader([]byte(body)))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, name)
+		}
+	}
+}